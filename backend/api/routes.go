@@ -11,5 +11,34 @@ func RegisterRoutes(mux *http.ServeMux, h handlers.Handler) {
 	mux.HandleFunc("POST /api/endpoints", h.PostEndpoint)
 	mux.HandleFunc("PATCH /api/endpoints/{id}", h.PatchEndpoint)
 	mux.HandleFunc("DELETE /api/endpoints/{id}", h.DeleteEndpoint)
+	mux.HandleFunc("GET /api/endpoints/{id}/history", h.GetEndpointHistory)
+	mux.HandleFunc("GET /api/endpoints/{id}/uptime", h.GetEndpointUptime)
+	mux.HandleFunc("GET /api/endpoints/{id}/latency", h.GetEndpointLatency)
+	mux.HandleFunc("GET /api/uptime", h.GetProjectUptime)
+	mux.HandleFunc("POST /api/heartbeat/{token}", h.PostHeartbeat)
+	mux.HandleFunc("POST /api/heartbeat/{token}/{signal}", h.PostHeartbeat)
 	mux.HandleFunc("GET /api/monitor-sse", h.MonitorSSE)
+	mux.HandleFunc("GET /metrics", h.GetMetrics)
+	mux.HandleFunc("GET /api/channels", h.GetChannels)
+	mux.HandleFunc("POST /api/channels", h.PostChannel)
+	mux.HandleFunc("PATCH /api/channels/{id}", h.PatchChannel)
+	mux.HandleFunc("DELETE /api/channels/{id}", h.DeleteChannel)
+	mux.HandleFunc("GET /api/channels/{id}/deliveries", h.GetChannelDeliveries)
+	mux.HandleFunc("GET /api/incidents", h.GetIncidents)
+	mux.HandleFunc("GET /api/incidents/{id}", h.GetIncident)
+	mux.HandleFunc("POST /api/incidents/{id}/acknowledge", h.PostIncidentAcknowledge)
+	mux.HandleFunc("POST /api/incidents/{id}/resolve", h.PostIncidentResolve)
+	mux.HandleFunc("POST /api/incidents/{id}/comments", h.PostIncidentComment)
+	mux.HandleFunc("GET /api/escalation-policies", h.GetEscalationPolicies)
+	mux.HandleFunc("POST /api/escalation-policies", h.PostEscalationPolicy)
+	mux.HandleFunc("PATCH /api/escalation-policies/{id}", h.PatchEscalationPolicy)
+	mux.HandleFunc("DELETE /api/escalation-policies/{id}", h.DeleteEscalationPolicy)
+	mux.HandleFunc("GET /api/schedules", h.GetSchedules)
+	mux.HandleFunc("POST /api/schedules", h.PostSchedule)
+	mux.HandleFunc("PATCH /api/schedules/{id}", h.PatchSchedule)
+	mux.HandleFunc("DELETE /api/schedules/{id}", h.DeleteSchedule)
+	mux.HandleFunc("GET /api/schedules/{id}/on-call", h.GetScheduleOnCall)
+	mux.HandleFunc("GET /api/schedules/{id}/overrides", h.GetScheduleOverrides)
+	mux.HandleFunc("POST /api/schedules/{id}/overrides", h.PostScheduleOverride)
+	mux.HandleFunc("DELETE /api/schedules/{id}/overrides/{override_id}", h.DeleteScheduleOverride)
 }
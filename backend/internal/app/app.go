@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"time"
@@ -9,42 +10,60 @@ import (
 	"github.com/wrtgvr/websites-monitor/internal/config"
 	"github.com/wrtgvr/websites-monitor/internal/handlers"
 	"github.com/wrtgvr/websites-monitor/internal/monitor"
+	"github.com/wrtgvr/websites-monitor/internal/notify"
 	"github.com/wrtgvr/websites-monitor/internal/storage"
 )
 
 type App struct {
-	mux     *http.ServeMux
-	storage storage.EndpointsStorage
+	mux       *http.ServeMux
+	storage   storage.Storage
+	scheduler *monitor.Scheduler
+	notifier  *notify.Notifier
+	escalator *notify.Escalator
 }
 
 func InitApp(local bool) *App {
 	//* storage
 	redisCfg := config.GetRedisConfig(true)
 	redisStorage := storage.NewRedisStorage(redisCfg)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	if appErr := redisStorage.BackfillProjectIDs(ctx); appErr != nil {
+		log.Printf("ERR: failed to backfill project list, err=%v\n", appErr)
+	}
+	cancel()
+
+	//* notifications
+	notifyCfg := config.GetNotifyConfig()
+	notifier := notify.NewNotifier(redisStorage, notifyCfg)
+	escalator := notify.NewEscalator(redisStorage, notifier, notifyCfg)
+
+	//* monitor
+	monitorCfg := config.GetMonitorConfig()
+	hub := monitor.NewHub()
+	scheduler := monitor.NewScheduler(redisStorage, hub, notifier, monitorCfg)
 
 	//* transport
 	responseTimeout := 5 * time.Second
 
-	h := handlers.NewHTTPHandler(redisStorage, responseTimeout)
+	h := handlers.NewHTTPHandler(redisStorage, hub, scheduler, responseTimeout, redisCfg.HistoryRetention)
 	mux := http.NewServeMux()
 
 	api.RegisterRoutes(mux, h)
 
-	//* monitor cfg
-	monitor.Config = &config.MonitorConfig{
-		Interval:        5 * time.Second,
-		PingTimeout:     5 * time.Second,
-		GorutinesAmount: 3,
-	}
-
 	//* app
 	return &App{
-		mux:     mux,
-		storage: redisStorage,
+		mux:       mux,
+		storage:   redisStorage,
+		scheduler: scheduler,
+		notifier:  notifier,
+		escalator: escalator,
 	}
 }
 
+// Start monitoring scheduler, escalator and http server. Blocks
 func (a *App) Run(addr string) error {
+	go a.scheduler.Run()
+	go a.escalator.Run()
 	return http.ListenAndServe(addr, a.mux)
 }
 
@@ -55,5 +74,9 @@ func (a *App) MustRun(addr string) {
 }
 
 func (a *App) Close() error {
+	// scheduler and escalator are stopped first, so they don't notify stopped notifier
+	a.scheduler.Stop()
+	a.escalator.Stop()
+	a.notifier.Stop()
 	return a.storage.Close()
 }
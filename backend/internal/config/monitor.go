@@ -2,24 +2,49 @@ package config
 
 import (
 	"flag"
+	"log"
 	"time"
 )
 
 type MonitorConfig struct {
-	Interval        time.Duration
+	// Check interval of endpoints without own interval
+	Interval time.Duration
+	// Check timeout of endpoints without own timeout
 	PingTimeout     time.Duration
 	GorutinesAmount int
+	// Interval between reloads of endpoints list from storage
+	RefreshInterval time.Duration
 }
 
 var (
 	// flags
-	flagMonitorInterval = flag.Int64("monitor_interval", 60, "interval (in sec) between endpoints pings")
+	flagMonitorInterval        = flag.Int64("monitor_interval", 60, "default interval (in sec) between endpoints pings")
+	flagMonitorTimeout         = flag.Int64("monitor_timeout", 10, "default timeout (in sec) of endpoint ping")
+	flagMonitorGorutines       = flag.Int("monitor_gorutines", 10, "max amount of concurrent endpoint pings")
+	flagMonitorRefreshInterval = flag.Int64("monitor_refresh_interval", 10, "interval (in sec) between endpoints list reloads")
 )
 
 func GetMonitorConfig() *MonitorConfig {
-	return &MonitorConfig{
-		Interval: getMonitorInterval(),
+	cfg := &MonitorConfig{
+		Interval:        getMonitorInterval(),
+		PingTimeout:     time.Duration(*flagMonitorTimeout) * time.Second,
+		GorutinesAmount: *flagMonitorGorutines,
+		RefreshInterval: time.Duration(*flagMonitorRefreshInterval) * time.Second,
 	}
+	// scheduler tickers panic on non-positive intervals, without workers nothing is checked
+	if cfg.Interval <= 0 {
+		log.Fatalf("monitor_interval must be positive, got %d\n", *flagMonitorInterval)
+	}
+	if cfg.PingTimeout <= 0 {
+		log.Fatalf("monitor_timeout must be positive, got %d\n", *flagMonitorTimeout)
+	}
+	if cfg.GorutinesAmount < 1 {
+		log.Fatalf("monitor_gorutines must be at least 1, got %d\n", *flagMonitorGorutines)
+	}
+	if cfg.RefreshInterval <= 0 {
+		log.Fatalf("monitor_refresh_interval must be positive, got %d\n", *flagMonitorRefreshInterval)
+	}
+	return cfg
 }
 
 func getMonitorInterval() time.Duration {
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"flag"
+	"log"
+	"time"
+)
+
+type NotifyConfig struct {
+	// Max delivery attempts of single notification to channel
+	MaxAttempts int
+	// Delay before first retry, doubled after every next attempt
+	RetryDelay time.Duration
+	// Upper bound of retry delay
+	MaxRetryDelay time.Duration
+	// Time limit of single delivery attempt
+	Timeout time.Duration
+	// Template of link attached to notifications, `{project_id}` and `{endpoint_id}` are replaced.
+	// Empty means no link
+	LinkURL string
+	// Interval between checks for due escalation steps
+	EscalationInterval time.Duration
+}
+
+const (
+	maxNotifyRetryDelay = 5 * time.Minute
+)
+
+var (
+	// flags
+	flagNotifyAttempts           = flag.Int("notify_attempts", 5, "max delivery attempts of single notification")
+	flagNotifyRetryDelay         = flag.Int64("notify_retry_delay", 1, "delay (in sec) before first notification retry, doubled after every next attempt")
+	flagNotifyTimeout            = flag.Int64("notify_timeout", 10, "timeout (in sec) of single notification delivery attempt")
+	flagNotifyLinkURL            = flag.String("notify_link_url", "", "link attached to notifications, {project_id} and {endpoint_id} are replaced")
+	flagNotifyEscalationInterval = flag.Int64("notify_escalation_interval", 10, "interval (in sec) between checks for due escalation steps")
+)
+
+func GetNotifyConfig() *NotifyConfig {
+	cfg := &NotifyConfig{
+		MaxAttempts:        *flagNotifyAttempts,
+		RetryDelay:         time.Duration(*flagNotifyRetryDelay) * time.Second,
+		MaxRetryDelay:      maxNotifyRetryDelay,
+		Timeout:            time.Duration(*flagNotifyTimeout) * time.Second,
+		LinkURL:            *flagNotifyLinkURL,
+		EscalationInterval: time.Duration(*flagNotifyEscalationInterval) * time.Second,
+	}
+	// expired attempt context fails every delivery, zero retry delay retries in a tight loop
+	if cfg.MaxAttempts < 1 {
+		log.Fatalf("notify_attempts must be at least 1, got %d\n", *flagNotifyAttempts)
+	}
+	if cfg.RetryDelay <= 0 {
+		log.Fatalf("notify_retry_delay must be positive, got %d\n", *flagNotifyRetryDelay)
+	}
+	if cfg.Timeout <= 0 {
+		log.Fatalf("notify_timeout must be positive, got %d\n", *flagNotifyTimeout)
+	}
+	// escalator ticker panics on non-positive interval
+	if cfg.EscalationInterval <= 0 {
+		log.Fatalf("notify_escalation_interval must be positive, got %d\n", *flagNotifyEscalationInterval)
+	}
+	return cfg
+}
@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"time"
 )
 
 type RedisConfig struct {
@@ -12,12 +13,26 @@ type RedisConfig struct {
 	DB              int
 	MaxEndpoints    int64
 	MaxReadOnlyKeys int64
+	MaxChannels     int64
+	// Max escalation policies per project
+	MaxEscalationPolicies int64
+	// Max on-call schedules per project
+	MaxSchedules int64
+	// Amount of last delivery attempts kept per notification channel
+	MaxDeliveries int64
+	// How long endpoint check history is kept
+	HistoryRetention time.Duration
 }
 
 const (
 	// constants
-	maxEndpoints    = 100
-	maxReadOnlyKeys = 20
+	maxEndpoints          = 100
+	maxReadOnlyKeys       = 20
+	maxChannels           = 20
+	maxEscalationPolicies = 20
+	maxSchedules          = 20
+	maxDeliveries         = 1000
+	historyRetention      = 90 * 24 * time.Hour
 	// env variables names
 	envVarRedisAddr = "REDIS_ADDR"
 	envVarRedisPass = "REDIS_PASS"
@@ -42,11 +57,16 @@ func GetRedisConfig(local bool) *RedisConfig {
 	}
 
 	return &RedisConfig{
-		Addr:            addr,
-		Password:        pass,
-		DB:              db,
-		MaxEndpoints:    maxEndpoints,
-		MaxReadOnlyKeys: maxReadOnlyKeys,
+		Addr:                  addr,
+		Password:              pass,
+		DB:                    db,
+		MaxEndpoints:          maxEndpoints,
+		MaxReadOnlyKeys:       maxReadOnlyKeys,
+		MaxChannels:           maxChannels,
+		MaxEscalationPolicies: maxEscalationPolicies,
+		MaxSchedules:          maxSchedules,
+		MaxDeliveries:         maxDeliveries,
+		HistoryRetention:      historyRetention,
 	}
 }
 
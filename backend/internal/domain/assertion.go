@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/wrtgvr/websites-monitor/internal/jsonpath"
+)
+
+// Type of response assertion
+type AssertionType string
+
+const (
+	// Body contains `Value`
+	AssertBodyContains AssertionType = "body_contains"
+	// Body does not contain `Value`
+	AssertBodyNotContains AssertionType = "body_not_contains"
+	// Body matches `Value` regex
+	AssertBodyRegex AssertionType = "body_regex"
+	// JSON body value at `Target` path compares to `Value` with `Operator`
+	AssertJSONPath AssertionType = "json_path"
+	// Any value of response header `Target` matches `Value` regex
+	AssertHeader AssertionType = "header"
+)
+
+// Operators of json path assertion
+const (
+	OpEqual        = "eq"
+	OpNotEqual     = "ne"
+	OpLess         = "lt"
+	OpLessEqual    = "lte"
+	OpGreater      = "gt"
+	OpGreaterEqual = "gte"
+	OpExists       = "exists"
+)
+
+var assertionOperators = []string{OpEqual, OpNotEqual, OpLess, OpLessEqual, OpGreater, OpGreaterEqual, OpExists}
+
+// Rule checked against http check response
+type Assertion struct {
+	Type AssertionType
+	// JSONPath expression or header name
+	Target string
+	// Only for json path assertion. Empty means `OpEqual`
+	Operator string
+	Value    string
+
+	// Compiled `Value` of body regex and header assertions, set by `Compile`
+	re *regexp.Regexp
+}
+
+func (a *Assertion) Validate() error {
+	switch a.Type {
+	case AssertBodyContains, AssertBodyNotContains:
+		if a.Value == "" {
+			return fmt.Errorf("%s assertion value cannot be empty", a.Type)
+		}
+	case AssertBodyRegex:
+		if err := a.Compile(); err != nil {
+			return fmt.Errorf("invalid %s assertion regex: %w", a.Type, err)
+		}
+	case AssertJSONPath:
+		if _, err := jsonpath.Parse(a.Target); err != nil {
+			return fmt.Errorf("invalid %s assertion target: %w", a.Type, err)
+		}
+		if a.Operator != "" && !containsString(assertionOperators, a.Operator) {
+			return fmt.Errorf("invalid %s assertion operator: %s", a.Type, a.Operator)
+		}
+	case AssertHeader:
+		if a.Target == "" {
+			return errors.New("header assertion target cannot be empty")
+		}
+		if err := a.Compile(); err != nil {
+			return fmt.Errorf("invalid %s assertion regex: %w", a.Type, err)
+		}
+	default:
+		return fmt.Errorf("unknown assertion type: %s", a.Type)
+	}
+	return nil
+}
+
+// Compile regex of body regex and header assertions once, so checks don't recompile it.
+// Done by `Validate`, assertions loaded from storage are compiled on load
+func (a *Assertion) Compile() error {
+	if a.Type != AssertBodyRegex && a.Type != AssertHeader {
+		return nil
+	}
+	re, err := regexp.Compile(a.Value)
+	if err != nil {
+		return err
+	}
+	a.re = re
+	return nil
+}
+
+// Compiled regex, nil if assertion was not compiled
+func (a *Assertion) Regexp() *regexp.Regexp {
+	return a.re
+}
+
+// Human readable rule, used in failure messages
+func (a *Assertion) String() string {
+	switch a.Type {
+	case AssertJSONPath:
+		op := a.Operator
+		if op == "" {
+			op = OpEqual
+		}
+		if op == OpExists {
+			return fmt.Sprintf("%s %s %s", a.Type, a.Target, op)
+		}
+		return fmt.Sprintf("%s %s %s %q", a.Type, a.Target, op, a.Value)
+	case AssertHeader:
+		return fmt.Sprintf("%s %s matches %q", a.Type, a.Target, a.Value)
+	default:
+		return fmt.Sprintf("%s %q", a.Type, a.Value)
+	}
+}
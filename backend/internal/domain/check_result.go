@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Endpoint state derived from check result
+type State string
+
+const (
+	StateUp       State = "up"
+	StateDown     State = "down"
+	StateDegraded State = "degraded"
+	StateUnknown  State = "unknown"
+	// Endpoint changes state too often, individual transitions are suppressed.
+	// Never a state of single check result
+	StateFlapping State = "flapping"
+)
+
+// Class of check failure
+type ErrorClass string
+
+const (
+	ErrorClassNone ErrorClass = ""
+	// Hostname resolution failed
+	ErrorClassDNS ErrorClass = "dns"
+	// Connection could not be established
+	ErrorClassConnect ErrorClass = "connect"
+	// TLS handshake or certificate verification failed
+	ErrorClassTLS ErrorClass = "tls"
+	// Check did not finish in time
+	ErrorClassTimeout ErrorClass = "timeout"
+	// Connection broke while reading response
+	ErrorClassRead ErrorClass = "read"
+	// Response was received, but its status code is not expected
+	ErrorClassStatusCode ErrorClass = "status_code"
+	// Response was received, but one of assertions failed
+	ErrorClassAssertion ErrorClass = "assertion"
+	// Heartbeat did not arrive in time or job reported failure
+	ErrorClassHeartbeat ErrorClass = "heartbeat"
+)
+
+// Whether error happened before full response was received
+func (c ErrorClass) Transport() bool {
+	switch c {
+	case ErrorClassDNS, ErrorClassConnect, ErrorClassTLS, ErrorClassTimeout, ErrorClassRead:
+		return true
+	}
+	return false
+}
+
+// Typed result of single endpoint check
+type CheckResult struct {
+	State      State
+	StatusCode int
+	ErrorClass ErrorClass
+	Error      string
+	// Total check duration
+	Duration time.Duration
+	// Websocket handshake and message round-trip durations, zero for other checks
+	Handshake time.Duration
+	RoundTrip time.Duration
+	// Server certificate, nil if check did no tls handshake
+	Cert *CertInfo
+}
+
+// Whether check result counts as uptime
+func (r *CheckResult) Up() bool {
+	return r.State == StateUp || r.State == StateDegraded
+}
+
+// Error of check prefixed with its class, e.g. `timeout: context deadline exceeded`.
+// Empty if check has no error
+func (r *CheckResult) Reason() string {
+	reason := strings.TrimSpace(r.Error)
+	switch {
+	case r.ErrorClass == ErrorClassNone:
+		return reason
+	case reason == "":
+		return string(r.ErrorClass)
+	default:
+		return fmt.Sprintf("%s: %s", r.ErrorClass, reason)
+	}
+}
@@ -0,0 +1,105 @@
+package domain
+
+import "time"
+
+// Max consecutive checks threshold
+const MaxConfirmationThreshold = 10
+
+// Confirmation of endpoint state by consecutive check results.
+// Endpoint goes down only after `FailureThreshold` failed checks in a row
+// and recovers only after `RecoveryThreshold` successful checks in a row
+type Confirmation struct {
+	// State of last check result, while endpoint state is the confirmed one
+	CheckState State
+	// Consecutive failed and successful checks
+	Failures  int
+	Successes int
+	// Time endpoint entered its confirmed state, zero if never checked
+	StateSince time.Time
+	// Last confirmed state other than flapping and time endpoint entered it.
+	// State changes are tracked between these states, so flapping period is skipped
+	StableState State
+	StableSince time.Time
+}
+
+// Whether last checks disagree with confirmed state, but are not enough to change it yet
+func (c *Confirmation) Confirming(state State) bool {
+	if state == StateUnknown || state == StateFlapping {
+		return false
+	}
+	if stateUp(state) {
+		return c.Failures > 0
+	}
+	return c.Successes > 0
+}
+
+// Failed checks in a row before endpoint is down
+func (ep *EndpointInfo) ConfirmFailures() int {
+	return max(ep.FailureThreshold, 1)
+}
+
+// Successful checks in a row before endpoint is up again
+func (ep *EndpointInfo) ConfirmSuccesses() int {
+	return max(ep.RecoveryThreshold, 1)
+}
+
+// Apply check result to status. Confirmed state changes only after enough
+// consecutive results disagree with it, switch between up and degraded is immediate.
+// Flapping endpoint stays in `StateFlapping` until flapping stops, then takes state of check result.
+// Never checked endpoint takes state of its first check result. Unknown check result
+// counts as failed one and confirms endpoint down.
+// Returns previous stable state and whether it changed: entering flapping is not a change,
+// leaving it is compared with state endpoint had before flapping
+func (st *EndpointStatus) Apply(ep *EndpointInfo, check *EndpointCheck) (prev State, changed bool) {
+	prev = st.State
+	initial := st.StateSince.IsZero()
+
+	st.LastChecked = check.CheckedAt
+	st.CheckResult = check.CheckResult
+	st.CheckState = check.State
+	if check.Up() {
+		st.Successes++
+		st.Failures = 0
+	} else {
+		st.Failures++
+		st.Successes = 0
+	}
+
+	wasFlapping := st.IsFlapping
+	st.FlapDetection.add(check.Up())
+
+	//* confirmed state
+	next := check.State
+	if !check.Up() {
+		next = StateDown
+	}
+	state := prev
+	switch {
+	case st.IsFlapping:
+		state = StateFlapping
+	case wasFlapping || initial || stateUp(prev) == check.Up():
+		state = next
+	case stateUp(prev) && st.Failures >= ep.ConfirmFailures():
+		state = next
+	case !stateUp(prev) && st.Successes >= ep.ConfirmSuccesses():
+		state = next
+	}
+
+	st.State = state
+	if state != prev {
+		st.StateSince = check.CheckedAt
+	}
+
+	//* stable state
+	prevStable := st.StableState
+	if state == StateFlapping || state == prevStable {
+		return prevStable, false
+	}
+	st.StableState = state
+	st.StableSince = check.CheckedAt
+	return prevStable, true
+}
+
+func stateUp(s State) bool {
+	return s == StateUp || s == StateDegraded
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+// Apply check results of given states one minute apart, returns result of the last `Apply`
+func applyStates(st *EndpointStatus, ep *EndpointInfo, start time.Time, states ...State) (prev State, changed bool) {
+	for i, s := range states {
+		prev, changed = st.Apply(ep, &EndpointCheck{
+			CheckedAt:   start.Add(time.Duration(i) * time.Minute),
+			CheckResult: CheckResult{State: s},
+		})
+	}
+	return prev, changed
+}
+
+func TestApplyThresholds(t *testing.T) {
+	tests := []struct {
+		name        string
+		failures    int
+		recoveries  int
+		initial     State
+		checks      []State
+		wantState   State
+		wantChanged bool
+	}{
+		{"first check sets state", 3, 3, StateUnknown, []State{StateDown}, StateDown, true},
+		{"single failure is not confirmed", 3, 1, StateUp, []State{StateDown}, StateUp, false},
+		{"failures below threshold", 3, 1, StateUp, []State{StateDown, StateDown}, StateUp, false},
+		{"failures reach threshold", 3, 1, StateUp, []State{StateDown, StateDown, StateDown}, StateDown, true},
+		{"success resets failures", 3, 1, StateUp, []State{StateDown, StateDown, StateUp, StateDown}, StateUp, false},
+		{"zero threshold counts as one", 0, 0, StateUp, []State{StateDown}, StateDown, true},
+		{"recoveries below threshold", 1, 2, StateDown, []State{StateUp}, StateDown, false},
+		{"recoveries reach threshold", 1, 2, StateDown, []State{StateUp, StateUp}, StateUp, true},
+		{"degraded is immediate", 3, 3, StateUp, []State{StateDegraded}, StateDegraded, true},
+		{"up after degraded is immediate", 3, 3, StateDegraded, []State{StateUp}, StateUp, true},
+		{"recovery to degraded", 1, 2, StateDown, []State{StateUp, StateDegraded}, StateDegraded, true},
+		{"unknown result confirms down", 2, 1, StateUp, []State{StateUnknown, StateUnknown}, StateDown, true},
+		{"unknown result keeps down", 1, 1, StateDown, []State{StateUnknown}, StateDown, false},
+		{"unknown first check sets down", 1, 1, StateUnknown, []State{StateUnknown}, StateDown, true},
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ep := &EndpointInfo{FailureThreshold: tt.failures, RecoveryThreshold: tt.recoveries}
+			st := &EndpointStatus{
+				CheckResult:  CheckResult{State: tt.initial},
+				Confirmation: Confirmation{StableState: tt.initial},
+			}
+			if tt.initial != StateUnknown {
+				st.StateSince = start.Add(-time.Hour)
+				st.StableSince = st.StateSince
+			}
+
+			prev, changed := applyStates(st, ep, start, tt.checks...)
+			if st.State != tt.wantState || changed != tt.wantChanged {
+				t.Fatalf("got state %s, changed %v; want state %s, changed %v", st.State, changed, tt.wantState, tt.wantChanged)
+			}
+			if changed && st.StateSince != st.LastChecked {
+				t.Errorf("state since %v is not time of confirming check %v", st.StateSince, st.LastChecked)
+			}
+			if len(tt.checks) == 1 && prev != tt.initial {
+				t.Errorf("got previous state %s, want %s", prev, tt.initial)
+			}
+		})
+	}
+}
+
+func TestApplyConfirmedUnknownIsNotInitial(t *testing.T) {
+	// status saved before unknown results were confirmed as down
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	st := &EndpointStatus{
+		CheckResult:  CheckResult{State: StateUnknown},
+		Confirmation: Confirmation{StateSince: start.Add(-time.Hour), StableState: StateUnknown, StableSince: start.Add(-time.Hour)},
+	}
+	ep := &EndpointInfo{RecoveryThreshold: 2}
+
+	if _, changed := applyStates(st, ep, start, StateUp); changed {
+		t.Fatalf("single success changed confirmed state to %s", st.State)
+	}
+	if _, changed := applyStates(st, ep, start.Add(time.Minute), StateUp); !changed || st.State != StateUp {
+		t.Fatalf("got state %s, changed %v; want up after recovery threshold", st.State, changed)
+	}
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Record types supported by dns check
+const (
+	DNSRecordA     = "A"
+	DNSRecordAAAA  = "AAAA"
+	DNSRecordCNAME = "CNAME"
+	DNSRecordMX    = "MX"
+	DNSRecordTXT   = "TXT"
+)
+
+var dnsRecordTypes = []string{DNSRecordA, DNSRecordAAAA, DNSRecordCNAME, DNSRecordMX, DNSRecordTXT}
+
+func validateDNSCheck(ep *EndpointInfo) error {
+	if strings.ContainsAny(ep.URL, "/: ") {
+		return errors.New("dns check url must be a hostname")
+	}
+	if ep.DNSRecordType != "" && !containsString(dnsRecordTypes, ep.DNSRecordType) {
+		return fmt.Errorf("dns record type must be one of: %s", strings.Join(dnsRecordTypes, ", "))
+	}
+	if ep.DNSResolver != "" {
+		if _, _, err := net.SplitHostPort(ep.DNSResolver); err != nil {
+			return errors.New("dns resolver must be in host:port format")
+		}
+	}
+	return nil
+}
@@ -1,25 +1,185 @@
 package domain
 
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
 type Endpoint struct {
-	ID           string
-	Name         string
-	URL          string
-	Status       string
-	LastChecked  string
-	ResponseTime string
-	ProjectId    string
+	EndpointInfo
+	// Zero if endpoint was never checked
+	LastChecked time.Time
+	CheckResult
+	Confirmation
+	FlapDetection
+	// Active incident, empty if endpoint has none
+	IncidentID string
 }
 
+// Kind of endpoint check
+type CheckType string
+
+const (
+	CheckTypeHTTP CheckType = "http"
+	CheckTypeTCP  CheckType = "tcp"
+	CheckTypeDNS  CheckType = "dns"
+	CheckTypeTLS  CheckType = "tls"
+	CheckTypeGRPC CheckType = "grpc"
+	CheckTypeWS   CheckType = "websocket"
+	// Push check, endpoint is pinged by monitored job
+	CheckTypeHeartbeat CheckType = "heartbeat"
+)
+
 type EndpointInfo struct {
-	ID        string
-	Name      string
+	ID   string
+	Name string
+	// Empty means `CheckTypeHTTP`
+	Type CheckType
+	// Check target: url for http and websocket checks, `host:port` for tcp and grpc checks,
+	// hostname for dns check, `host[:port]` for tls check. Not used by heartbeat check
 	URL       string
 	ProjectId string
+	// Target uptime percentage, e.g. 99.9. Zero means `DefaultSLOTarget`
+	SLOTarget float64
+	// Policy escalating unacknowledged incidents of endpoint. Empty means no escalation
+	EscalationPolicyID string
+
+	//* schedule
+
+	// Time between checks. Zero means monitor default
+	Interval time.Duration
+	// Time limit of single check attempt. Zero means monitor default
+	Timeout time.Duration
+	// Extra attempts of failed check before endpoint is declared down
+	Retries int
+	// Consecutive failed checks before endpoint is down. Zero means 1
+	FailureThreshold int
+	// Consecutive successful checks before endpoint is up again. Zero means 1
+	RecoveryThreshold int
+	// Check interval while state is being confirmed. Zero means regular interval
+	RecheckInterval time.Duration
+
+	//* http check
+
+	// Empty means GET
+	Method string
+	// Also sent as grpc check metadata and websocket handshake headers
+	Headers []Header
+	Body    string
+	// Empty means any 2xx
+	ExpectedStatuses StatusRanges
+	// Empty means `RedirectFollow`
+	RedirectPolicy string
+	// Checked in order, first failed one fails the check
+	Assertions []Assertion
+
+	//* tcp check
+
+	// Sent after connect
+	TCPPayload string
+	// Expected prefix of data received after connect (and payload)
+	TCPExpectBanner string
+
+	//* dns check
+
+	// Empty means `DNSRecordA`
+	DNSRecordType string
+	// `host:port` of resolver. Empty means system resolver
+	DNSResolver string
+	// Values that must be among answers. Empty means any answer
+	DNSExpected []string
+
+	//* grpc check
+
+	// Service name passed to health check. Empty means overall server health
+	GRPCService string
+	// Connect with tls instead of plaintext
+	GRPCTLS bool
+
+	//* websocket check
+
+	// Sent after handshake
+	WSMessage string
+	// Regular expression reply must match. Empty means any reply if message is set
+	WSExpectPattern string
+	// Compiled `WSExpectPattern`, set by `CompileWSPattern`
+	wsPattern *regexp.Regexp
+
+	//* heartbeat check
+
+	// Secret token of heartbeat url, generated on creation
+	HeartbeatToken string
+	// Expected interval between heartbeats
+	HeartbeatPeriod time.Duration
+	// Extra time after period before endpoint is down
+	HeartbeatGrace time.Duration
+
+	//* tls (also applies to https and grpc with tls checks)
+
+	// Endpoint is degraded when certificate expires within this amount of days.
+	// Zero means `DefaultTLSExpiryThresholdDays`
+	TLSExpiryThresholdDays int
 }
 
+// Check type with default applied
+func (ep *EndpointInfo) CheckType() CheckType {
+	if ep.Type == "" {
+		return CheckTypeHTTP
+	}
+	return ep.Type
+}
+
+func (ep *EndpointInfo) Validate() error {
+	if ep.URL == "" && ep.CheckType() != CheckTypeHeartbeat {
+		return errors.New("url cannot be empty")
+	}
+	if ep.SLOTarget < 0 || ep.SLOTarget >= 100 {
+		return errors.New("slo target must be in range (0, 100)")
+	}
+	if ep.TLSExpiryThresholdDays < 0 {
+		return errors.New("tls expiry threshold cannot be negative")
+	}
+	if err := validateSchedule(ep); err != nil {
+		return err
+	}
+
+	switch ep.CheckType() {
+	case CheckTypeHTTP:
+		return validateHTTPCheck(ep)
+	case CheckTypeTCP:
+		return validateTCPCheck(ep)
+	case CheckTypeDNS:
+		return validateDNSCheck(ep)
+	case CheckTypeTLS:
+		return validateTLSCheck(ep)
+	case CheckTypeGRPC:
+		return validateGRPCCheck(ep)
+	case CheckTypeWS:
+		return validateWSCheck(ep)
+	case CheckTypeHeartbeat:
+		return validateHeartbeatCheck(ep)
+	default:
+		return fmt.Errorf("unknown check type: %s", ep.Type)
+	}
+}
+
+// Endpoint status. State of check result is confirmed state, see `Confirmation`
 type EndpointStatus struct {
-	ID           string
-	Status       string
-	LastChecked  string
-	ResponseTime string
+	ID          string
+	ProjectId   string
+	LastChecked time.Time
+	CheckResult
+	Confirmation
+	FlapDetection
+	// Active incident, empty if endpoint has none
+	IncidentID string
+}
+
+// Single check result, saved to endpoint history
+type EndpointCheck struct {
+	EndpointID string
+	CheckedAt  time.Time
+	CheckResult
 }
@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+const (
+	// Max steps of escalation policy
+	MaxEscalationSteps = 10
+	// Max channels notified by single escalation step
+	MaxEscalationStepChannels = 10
+	// Max on-call schedules of single escalation step
+	MaxEscalationStepSchedules = 5
+	// Min total delay of policy steps, so repeated steps don't flood channels
+	MinEscalationCycle = time.Minute
+)
+
+// Project escalation policy, notifies channels about open incidents nobody acknowledged.
+// Steps run in order, then again from first one until incident is acknowledged or resolved
+type EscalationPolicy struct {
+	ID        string
+	ProjectId string
+	Name      string
+	Steps     []EscalationStep
+}
+
+type EscalationStep struct {
+	// Time after previous step, after incident opening for first step
+	Delay time.Duration
+	// Notified channels, deleted and disabled channels are skipped
+	ChannelIDs []string
+	// Schedules whose on-call participants are notified through step channels, deleted schedules are skipped
+	ScheduleIDs []string
+}
+
+func (p *EscalationPolicy) Validate() error {
+	if p.Name == "" {
+		return errors.New("name cannot be empty")
+	}
+	if len(p.Steps) == 0 {
+		return errors.New("steps cannot be empty")
+	}
+	if len(p.Steps) > MaxEscalationSteps {
+		return fmt.Errorf("policy cannot have more than %d steps", MaxEscalationSteps)
+	}
+
+	var cycle time.Duration
+	for i, step := range p.Steps {
+		if step.Delay < 0 {
+			return fmt.Errorf("step %d: delay cannot be negative", i+1)
+		}
+		if len(step.ChannelIDs) == 0 {
+			return fmt.Errorf("step %d: channels cannot be empty", i+1)
+		}
+		if len(step.ChannelIDs) > MaxEscalationStepChannels {
+			return fmt.Errorf("step %d: step cannot have more than %d channels", i+1, MaxEscalationStepChannels)
+		}
+		for j, id := range step.ChannelIDs {
+			if slices.Contains(step.ChannelIDs[:j], id) {
+				return fmt.Errorf("step %d: duplicate channel %s", i+1, id)
+			}
+		}
+		if len(step.ScheduleIDs) > MaxEscalationStepSchedules {
+			return fmt.Errorf("step %d: step cannot have more than %d schedules", i+1, MaxEscalationStepSchedules)
+		}
+		for j, id := range step.ScheduleIDs {
+			if slices.Contains(step.ScheduleIDs[:j], id) {
+				return fmt.Errorf("step %d: duplicate schedule %s", i+1, id)
+			}
+		}
+		cycle += step.Delay
+	}
+	if cycle < MinEscalationCycle {
+		return fmt.Errorf("total delay of steps cannot be less than %s", MinEscalationCycle)
+	}
+	return nil
+}
+
+// Pending escalation of open incident
+type Escalation struct {
+	ProjectId  string
+	IncidentID string
+	PolicyID   string
+	// Index of next step
+	Step int
+	// Rounds of policy steps done, every round starts from first step
+	Round int
+	// Time next step is due
+	DueAt time.Time
+}
+
+// Escalation of incident opened at `at`, starts from first step
+func (p *EscalationPolicy) Start(incidentId string, at time.Time) *Escalation {
+	return &Escalation{
+		ProjectId:  p.ProjectId,
+		IncidentID: incidentId,
+		PolicyID:   p.ID,
+		DueAt:      at.Add(p.Steps[0].Delay),
+	}
+}
+
+// Step escalation is due for. Index is wrapped if policy lost steps after escalation started
+func (p *EscalationPolicy) Current(e *Escalation) (int, *EscalationStep) {
+	i := e.Step % len(p.Steps)
+	return i, &p.Steps[i]
+}
+
+// Move escalation to step after current one, `at` is time current step ran
+func (p *EscalationPolicy) Advance(e *Escalation, at time.Time) {
+	i, _ := p.Current(e)
+	e.Step = i + 1
+	if e.Step == len(p.Steps) {
+		e.Step = 0
+		e.Round++
+	}
+	e.DueAt = at.Add(p.Steps[e.Step].Delay)
+}
@@ -0,0 +1,74 @@
+package domain
+
+import "math/bits"
+
+// Flap detection thresholds of weighted state change percentage, Nagios-style.
+// Endpoint starts flapping above high threshold and stops below low one
+const (
+	FlapHighThreshold = 50.0
+	FlapLowThreshold  = 25.0
+)
+
+const (
+	// Check results kept for flap detection
+	flapWindow = 21
+	// Results required before flapping can be detected
+	flapMinResults = 10
+)
+
+// Flapping detection by rate of state changes among recent check results
+type FlapDetection struct {
+	// Recent check results, bit is set if check was up, newest result in lowest bit
+	Recent uint32
+	// Amount of results in `Recent`
+	RecentCount int
+	// Weighted percentage of state changes among recent results
+	FlapScore  float64
+	IsFlapping bool
+}
+
+// Add check result, recalculate score and flapping state
+func (f *FlapDetection) add(up bool) {
+	f.Recent <<= 1
+	if up {
+		f.Recent |= 1
+	}
+	f.Recent &= 1<<flapWindow - 1
+	f.RecentCount = min(f.RecentCount+1, flapWindow)
+
+	f.FlapScore = f.score()
+	switch {
+	case !f.IsFlapping && f.RecentCount >= flapMinResults && f.FlapScore >= FlapHighThreshold:
+		f.IsFlapping = true
+	case f.IsFlapping && f.FlapScore < FlapLowThreshold:
+		f.IsFlapping = false
+	}
+}
+
+// Percentage of state changes between consecutive results.
+// Changes are weighted linearly from 0.8 for the oldest to 1.2 for the newest
+func (f *FlapDetection) score() float64 {
+	changes := f.RecentCount - 1
+	if changes < 1 {
+		return 0
+	}
+	// bit i is set if results i and i+1 differ
+	diff := (f.Recent ^ f.Recent>>1) & (1<<changes - 1)
+	if bits.OnesCount32(diff) == 0 {
+		return 0
+	}
+
+	var weighted float64
+	for i := range changes {
+		if diff&(1<<i) == 0 {
+			continue
+		}
+		// i = 0 is the newest change
+		weight := 1.2
+		if changes > 1 {
+			weight = 1.2 - 0.4*float64(i)/float64(changes-1)
+		}
+		weighted += weight
+	}
+	return weighted / float64(changes) * 100
+}
@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// Add results to flap detection, oldest first
+func addResults(f *FlapDetection, results ...bool) {
+	for _, up := range results {
+		f.add(up)
+	}
+}
+
+// Alternating results, starting with up
+func alternating(n int) []bool {
+	res := make([]bool, n)
+	for i := range res {
+		res[i] = i%2 == 0
+	}
+	return res
+}
+
+func TestFlapScore(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []bool
+		want    float64
+	}{
+		{"no results", nil, 0},
+		{"single result", []bool{false}, 0},
+		{"stable results", []bool{true, true, true, true}, 0},
+		{"only newest change", []bool{true, true, false}, 60},
+		{"only oldest change", []bool{false, true, true}, 40},
+		{"every result changes", alternating(flapWindow), 100},
+		{"window keeps newest results", append(alternating(flapWindow), make([]bool, flapWindow)...), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var f FlapDetection
+			addResults(&f, tt.results...)
+			if math.Abs(f.FlapScore-tt.want) > 1e-9 {
+				t.Fatalf("got score %v, want %v", f.FlapScore, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlapDetection(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []bool
+		want    bool
+	}{
+		{"too few results", alternating(flapMinResults - 1), false},
+		{"starts above high threshold", alternating(flapMinResults), true},
+		{"keeps flapping between thresholds", append(alternating(flapWindow), true, true, true, true, true, true), true},
+		{"stops below low threshold", append(alternating(flapWindow), make([]bool, 17)...), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var f FlapDetection
+			addResults(&f, tt.results...)
+			if f.IsFlapping != tt.want {
+				t.Fatalf("got flapping %v with score %v, want %v", f.IsFlapping, f.FlapScore, tt.want)
+			}
+			if f.IsFlapping && f.FlapScore < FlapLowThreshold {
+				t.Errorf("flapping with score %v below low threshold", f.FlapScore)
+			}
+		})
+	}
+}
+
+func TestApplyFlapping(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	results := map[bool]State{true: StateUp, false: StateDown}
+
+	tests := []struct {
+		name        string
+		stable      State
+		after       bool
+		wantChanged bool
+	}{
+		{"up before and after flapping", StateUp, true, false},
+		{"up before and down after flapping", StateUp, false, true},
+		{"down before and up after flapping", StateDown, true, true},
+		{"down before and after flapping", StateDown, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// alternating results never confirm state change on their own
+			ep := &EndpointInfo{FailureThreshold: MaxConfirmationThreshold, RecoveryThreshold: MaxConfirmationThreshold}
+			st := &EndpointStatus{
+				CheckResult:  CheckResult{State: tt.stable},
+				Confirmation: Confirmation{StateSince: start, StableState: tt.stable, StableSince: start},
+			}
+			at := start
+
+			// alternate results until endpoint is flapping, entering flapping is not a change
+			for i := 0; !st.IsFlapping; i++ {
+				if i > flapWindow {
+					t.Fatal("endpoint never started flapping")
+				}
+				at = at.Add(time.Minute)
+				if _, changed := st.Apply(ep, &EndpointCheck{CheckedAt: at, CheckResult: CheckResult{State: results[i%2 == 0]}}); changed {
+					t.Fatalf("check %d before flapping changed state to %s", i, st.State)
+				}
+			}
+
+			// same results until flapping stops
+			var prev State
+			var changed bool
+			for st.IsFlapping {
+				at = at.Add(time.Minute)
+				prev, changed = st.Apply(ep, &EndpointCheck{CheckedAt: at, CheckResult: CheckResult{State: results[tt.after]}})
+				if st.IsFlapping && changed {
+					t.Fatal("check during flapping is reported as change")
+				}
+			}
+
+			if st.State != results[tt.after] || prev != tt.stable || changed != tt.wantChanged {
+				t.Fatalf("got state %s, previous %s, changed %v; want state %s, previous %s, changed %v",
+					st.State, prev, changed, results[tt.after], tt.stable, tt.wantChanged)
+			}
+			if st.StableState != st.State {
+				t.Errorf("got stable state %s, want %s", st.StableState, st.State)
+			}
+			if !changed && !st.StableSince.Equal(start) {
+				t.Errorf("stable since moved to %v although state did not change", st.StableSince)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"errors"
+	"net"
+	"strings"
+)
+
+func validateGRPCCheck(ep *EndpointInfo) error {
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(ep.URL, "grpc://"))
+	if err != nil || host == "" || port == "" {
+		return errors.New("grpc check url must be in host:port format")
+	}
+	for _, h := range ep.Headers {
+		name := strings.ToLower(strings.TrimSpace(h.Name))
+		if name == "" {
+			return errors.New("metadata key cannot be empty")
+		}
+		if strings.HasPrefix(name, "grpc-") {
+			return errors.New("metadata keys with grpc- prefix are reserved")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Bounds of heartbeat period
+const (
+	MinHeartbeatPeriod = time.Minute
+	MaxHeartbeatPeriod = 31 * 24 * time.Hour
+)
+
+// Signal sent by monitored job to its heartbeat url
+type HeartbeatSignal string
+
+const (
+	// Job run started, run duration is measured from it
+	HeartbeatStart HeartbeatSignal = "start"
+	// Job run finished successfully
+	HeartbeatSuccess HeartbeatSignal = "success"
+	// Job run failed
+	HeartbeatFail HeartbeatSignal = "fail"
+)
+
+func (s HeartbeatSignal) Valid() bool {
+	switch s {
+	case HeartbeatStart, HeartbeatSuccess, HeartbeatFail:
+		return true
+	}
+	return false
+}
+
+// Heartbeat endpoint state between signals
+type HeartbeatState struct {
+	// Endpoint is down if no heartbeat arrives before this time
+	DueAt time.Time
+	// Start of current job run, zero if job is not running
+	StartedAt time.Time
+}
+
+// Time next heartbeat is due if last one arrived at `at`
+func (ep *EndpointInfo) HeartbeatDueAt(at time.Time) time.Time {
+	return at.Add(ep.HeartbeatPeriod + ep.HeartbeatGrace)
+}
+
+func validateHeartbeatCheck(ep *EndpointInfo) error {
+	if ep.HeartbeatPeriod < MinHeartbeatPeriod || ep.HeartbeatPeriod > MaxHeartbeatPeriod {
+		return errors.New("heartbeat period must be in range 1m-31d")
+	}
+	if ep.HeartbeatGrace < 0 {
+		return errors.New("heartbeat grace cannot be negative")
+	}
+	return nil
+}
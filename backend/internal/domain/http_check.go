@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Redirect policies of http check
+const (
+	RedirectFollow = "follow"
+	RedirectNone   = "none"
+)
+
+// Max amount of assertions per endpoint
+const MaxAssertions = 20
+
+// Methods allowed for http check
+var HTTPCheckMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodOptions,
+}
+
+// Header sent with http check request
+type Header struct {
+	Name  string
+	Value string
+}
+
+// Inclusive range of http status codes
+type StatusRange struct {
+	From int
+	To   int
+}
+
+// Set of expected http status codes. Empty set means any 2xx
+type StatusRanges []StatusRange
+
+// Parse comma separated status codes and ranges, e.g. `200-299,401`
+func ParseStatusRanges(s string) (StatusRanges, error) {
+	var ranges StatusRanges
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		fromStr, toStr, isRange := strings.Cut(part, "-")
+		if !isRange {
+			toStr = fromStr
+		}
+		from, err := strconv.Atoi(strings.TrimSpace(fromStr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid status code: %s", part)
+		}
+		to, err := strconv.Atoi(strings.TrimSpace(toStr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid status code: %s", part)
+		}
+		if from < 100 || to > 599 || from > to {
+			return nil, fmt.Errorf("invalid status code range: %s", part)
+		}
+		ranges = append(ranges, StatusRange{From: from, To: to})
+	}
+	return ranges, nil
+}
+
+// Format ranges in format accepted by `ParseStatusRanges`
+func (r StatusRanges) String() string {
+	parts := make([]string, len(r))
+	for i, v := range r {
+		if v.From == v.To {
+			parts[i] = strconv.Itoa(v.From)
+		} else {
+			parts[i] = fmt.Sprintf("%d-%d", v.From, v.To)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func (r StatusRanges) Contains(code int) bool {
+	if len(r) == 0 {
+		return code >= 200 && code < 300
+	}
+	for _, v := range r {
+		if code >= v.From && code <= v.To {
+			return true
+		}
+	}
+	return false
+}
+
+func validateHTTPCheck(ep *EndpointInfo) error {
+	u, err := url.Parse(ep.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("url must be absolute http or https url")
+	}
+	if ep.Method != "" && !containsString(HTTPCheckMethods, ep.Method) {
+		return fmt.Errorf("method must be one of: %s", strings.Join(HTTPCheckMethods, ", "))
+	}
+	for _, h := range ep.Headers {
+		if strings.TrimSpace(h.Name) == "" {
+			return errors.New("header name cannot be empty")
+		}
+	}
+	if ep.RedirectPolicy != "" && ep.RedirectPolicy != RedirectFollow && ep.RedirectPolicy != RedirectNone {
+		return fmt.Errorf("redirect policy must be one of: %s, %s", RedirectFollow, RedirectNone)
+	}
+	if len(ep.Assertions) > MaxAssertions {
+		return fmt.Errorf("endpoint cannot have more than %d assertions", MaxAssertions)
+	}
+	for i := range ep.Assertions {
+		if err := ep.Assertions[i].Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
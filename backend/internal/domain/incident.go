@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// Incident lifecycle state
+type IncidentState string
+
+const (
+	// Endpoint is down, nobody reacted yet
+	IncidentOpen IncidentState = "open"
+	// Somebody is working on incident
+	IncidentAcknowledged IncidentState = "acknowledged"
+	// Endpoint recovered or incident was closed manually
+	IncidentResolved IncidentState = "resolved"
+)
+
+func (s IncidentState) Valid() bool {
+	switch s {
+	case IncidentOpen, IncidentAcknowledged, IncidentResolved:
+		return true
+	}
+	return false
+}
+
+// Downtime of single endpoint, opened when endpoint goes down and resolved when it recovers
+type Incident struct {
+	ID         string
+	ProjectId  string
+	EndpointID string
+	// Endpoint name at opening, kept after endpoint is deleted
+	EndpointName string
+	State        IncidentState
+	// Failure reason of check that opened incident
+	Cause    string
+	OpenedAt time.Time
+	// Zero and empty if incident was not acknowledged
+	AcknowledgedAt time.Time
+	AcknowledgedBy string
+	// Zero if incident is not resolved
+	ResolvedAt time.Time
+	// Empty if incident was resolved automatically
+	ResolvedBy string
+}
+
+// Whether incident is not resolved yet
+func (inc *Incident) Active() bool {
+	return inc.State != IncidentResolved
+}
+
+func (inc *Incident) Acknowledge(at time.Time, by string) error {
+	switch inc.State {
+	case IncidentAcknowledged:
+		return errors.New("incident is already acknowledged")
+	case IncidentResolved:
+		return errors.New("incident is already resolved")
+	}
+	inc.State = IncidentAcknowledged
+	inc.AcknowledgedAt = at
+	inc.AcknowledgedBy = by
+	return nil
+}
+
+// Resolve incident. Empty `by` means incident is resolved automatically
+func (inc *Incident) Resolve(at time.Time, by string) error {
+	if !inc.Active() {
+		return errors.New("incident is already resolved")
+	}
+	inc.State = IncidentResolved
+	inc.ResolvedAt = at
+	inc.ResolvedBy = by
+	return nil
+}
+
+// Time incident lasted, until `now` if it is not resolved
+func (inc *Incident) Duration(now time.Time) time.Duration {
+	if inc.Active() {
+		return now.Sub(inc.OpenedAt)
+	}
+	return inc.ResolvedAt.Sub(inc.OpenedAt)
+}
+
+// Kind of incident timeline entry
+type TimelineEntryType string
+
+const (
+	// Incident state changed
+	TimelineState TimelineEntryType = "state"
+	// Check result of endpoint
+	TimelineCheck TimelineEntryType = "check"
+	// Notification about endpoint state change was delivered or failed
+	TimelineNotification TimelineEntryType = "notification"
+	// Comment left through api
+	TimelineComment TimelineEntryType = "comment"
+	// Escalation step notified channels
+	TimelineEscalation TimelineEntryType = "escalation"
+)
+
+// Max length of timeline comment
+const MaxCommentLength = 4096
+
+// Single event of incident timeline
+type TimelineEntry struct {
+	At   time.Time
+	Type TimelineEntryType
+	// New incident state of state entry, check state of check entry
+	State   string
+	Message string
+	// Empty for automatic entries
+	Author string
+}
+
+// Incidents list filter, zero fields match any incident
+type IncidentFilter struct {
+	States     []IncidentState
+	EndpointID string
+}
+
+func (f *IncidentFilter) Match(inc *Incident) bool {
+	if len(f.States) > 0 && !slices.Contains(f.States, inc.State) {
+		return false
+	}
+	if f.EndpointID != "" && inc.EndpointID != f.EndpointID {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// Histogram upper bounds used when none are given
+var DefaultLatencyBuckets = []time.Duration{
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// Latency distribution of endpoint checks over time window
+type LatencyStats struct {
+	From  time.Time
+	To    time.Time
+	Count int64
+	Min   time.Duration
+	Max   time.Duration
+	Mean  time.Duration
+	P50   time.Duration
+	P90   time.Duration
+	P95   time.Duration
+	P99   time.Duration
+	// Non-cumulative buckets in ascending order, last one has no upper bound
+	Histogram []*LatencyBucket
+}
+
+type LatencyBucket struct {
+	// Inclusive upper bound. Zero means +Inf
+	UpperBound time.Duration
+	Count      int64
+}
+
+// Compute latency stats of checks within [from, to].
+// Checks without full response (connection errors etc.) are ignored
+func ComputeLatencyStats(checks []*EndpointCheck, from, to time.Time, buckets []time.Duration) *LatencyStats {
+	lc := NewLatencyCollector(from, to, buckets)
+	for _, c := range checks {
+		lc.Add(c)
+	}
+	return lc.Stats()
+}
+
+// Collects latencies check by check, so history can be streamed instead of loaded at once
+type LatencyCollector struct {
+	stats     *LatencyStats
+	buckets   []time.Duration
+	latencies []time.Duration
+	sum       time.Duration
+}
+
+// Collector of checks within [from, to] with given histogram upper bounds, default ones if empty
+func NewLatencyCollector(from, to time.Time, buckets []time.Duration) *LatencyCollector {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+	buckets = slices.Compact(buckets)
+
+	st := &LatencyStats{
+		From:      from,
+		To:        to,
+		Histogram: make([]*LatencyBucket, len(buckets)+1),
+	}
+	for i, b := range buckets {
+		st.Histogram[i] = &LatencyBucket{UpperBound: b}
+	}
+	st.Histogram[len(buckets)] = &LatencyBucket{}
+
+	return &LatencyCollector{
+		stats:   st,
+		buckets: buckets,
+	}
+}
+
+// Add check latency. Check outside of window or without full response is ignored
+func (lc *LatencyCollector) Add(c *EndpointCheck) {
+	if c.CheckedAt.Before(lc.stats.From) || c.CheckedAt.After(lc.stats.To) || c.ErrorClass.Transport() {
+		return
+	}
+	lc.latencies = append(lc.latencies, c.Duration)
+	lc.sum += c.Duration
+
+	i, _ := slices.BinarySearch(lc.buckets, c.Duration)
+	lc.stats.Histogram[i].Count++
+}
+
+// Stats of added checks
+func (lc *LatencyCollector) Stats() *LatencyStats {
+	st := lc.stats
+	latencies := lc.latencies
+	if len(latencies) == 0 {
+		return st
+	}
+
+	slices.Sort(latencies)
+	st.Count = int64(len(latencies))
+	st.Min = latencies[0]
+	st.Max = latencies[len(latencies)-1]
+	st.Mean = lc.sum / time.Duration(len(latencies))
+	st.P50 = percentile(latencies, 50)
+	st.P90 = percentile(latencies, 90)
+	st.P95 = percentile(latencies, 95)
+	st.P99 = percentile(latencies, 99)
+
+	return st
+}
+
+// Nearest-rank percentile of sorted non-empty slice
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	// p*n is exact for whole percentiles, so exact ranks are not rounded up
+	rank := int(math.Ceil(p*float64(len(sorted))/100)) - 1
+	rank = max(0, min(rank, len(sorted)-1))
+	return sorted[rank]
+}
+
+// Duration in milliseconds with fractional part
+func Milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+// Durations of given milliseconds
+func msDurations(ms ...int) []time.Duration {
+	res := make([]time.Duration, len(ms))
+	for i, v := range ms {
+		res[i] = time.Duration(v) * time.Millisecond
+	}
+	return res
+}
+
+func TestPercentile(t *testing.T) {
+	tests := []struct {
+		name   string
+		sorted []time.Duration
+		p      float64
+		want   time.Duration
+	}{
+		{"single value", msDurations(7), 99, 7 * time.Millisecond},
+		{"p50 of two", msDurations(1, 2), 50, 1 * time.Millisecond},
+		{"p90 of six", msDurations(1, 2, 3, 4, 5, 6), 90, 6 * time.Millisecond},
+		{"p50 of six", msDurations(1, 2, 3, 4, 5, 6), 50, 3 * time.Millisecond},
+		{"exact rank is not rounded up", msDurations(1, 2, 3, 4, 5, 6, 7, 8, 9, 10), 90, 9 * time.Millisecond},
+		{"rank just above exact", msDurations(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11), 90, 10 * time.Millisecond},
+		{"p95 of twenty", msDurations(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20), 95, 19 * time.Millisecond},
+		{"p99 of three", msDurations(1, 2, 3), 99, 3 * time.Millisecond},
+		{"p0 is minimum", msDurations(1, 2, 3), 0, 1 * time.Millisecond},
+		{"p100 is maximum", msDurations(1, 2, 3), 100, 3 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := percentile(tt.sorted, tt.p); got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeLatencyStats(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	check := func(at time.Duration, ms int, class ErrorClass) *EndpointCheck {
+		return &EndpointCheck{
+			CheckedAt:   from.Add(at),
+			CheckResult: CheckResult{Duration: time.Duration(ms) * time.Millisecond, ErrorClass: class},
+		}
+	}
+	buckets := msDurations(100, 10, 100)
+
+	tests := []struct {
+		name      string
+		checks    []*EndpointCheck
+		wantCount int64
+		wantMin   time.Duration
+		wantMax   time.Duration
+		wantMean  time.Duration
+		wantP90   time.Duration
+		// counts of buckets <=10ms, <=100ms, +Inf
+		wantHist [3]int64
+	}{
+		{"no checks", nil, 0, 0, 0, 0, 0, [3]int64{}},
+		{
+			"bucket bounds are inclusive",
+			[]*EndpointCheck{check(0, 10, ""), check(time.Minute, 11, ""), check(2*time.Minute, 100, ""), check(3*time.Minute, 101, "")},
+			4, 10 * time.Millisecond, 101 * time.Millisecond, 55500 * time.Microsecond, 101 * time.Millisecond, [3]int64{1, 2, 1},
+		},
+		{
+			"window bounds are inclusive",
+			[]*EndpointCheck{check(-time.Second, 1, ""), check(0, 5, ""), check(time.Hour, 50, ""), check(time.Hour+time.Second, 1, "")},
+			2, 5 * time.Millisecond, 50 * time.Millisecond, 27500 * time.Microsecond, 50 * time.Millisecond, [3]int64{1, 1, 0},
+		},
+		{
+			"transport failures are ignored",
+			[]*EndpointCheck{check(0, 20, ""), check(time.Minute, 5000, ErrorClassTimeout), check(2*time.Minute, 30, ErrorClassStatusCode)},
+			2, 20 * time.Millisecond, 30 * time.Millisecond, 25 * time.Millisecond, 30 * time.Millisecond, [3]int64{0, 2, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := ComputeLatencyStats(tt.checks, from, to, buckets)
+			if st.Count != tt.wantCount || st.Min != tt.wantMin || st.Max != tt.wantMax || st.Mean != tt.wantMean || st.P90 != tt.wantP90 {
+				t.Fatalf("got count %d, min %v, max %v, mean %v, p90 %v; want %d, %v, %v, %v, %v",
+					st.Count, st.Min, st.Max, st.Mean, st.P90, tt.wantCount, tt.wantMin, tt.wantMax, tt.wantMean, tt.wantP90)
+			}
+			if len(st.Histogram) != 3 {
+				t.Fatalf("got %d buckets, want duplicate bounds to be merged into 3", len(st.Histogram))
+			}
+			for i, b := range st.Histogram {
+				if b.Count != tt.wantHist[i] {
+					t.Errorf("bucket %d with bound %v has %d checks, want %d", i, b.UpperBound, b.Count, tt.wantHist[i])
+				}
+			}
+			if st.Histogram[0].UpperBound != 10*time.Millisecond || st.Histogram[2].UpperBound != 0 {
+				t.Errorf("buckets are not sorted with +Inf last")
+			}
+		})
+	}
+}
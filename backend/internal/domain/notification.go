@@ -0,0 +1,269 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"time"
+)
+
+// Kind of notification channel
+type ChannelType string
+
+const (
+	// Signed JSON payload posted to url
+	ChannelTypeWebhook ChannelType = "webhook"
+	// Email sent through smtp server
+	ChannelTypeSMTP ChannelType = "smtp"
+	// Chat messages posted to incoming webhook url
+	ChannelTypeSlack   ChannelType = "slack"
+	ChannelTypeDiscord ChannelType = "discord"
+	ChannelTypeTeams   ChannelType = "teams"
+	// Message sent with Telegram Bot API
+	ChannelTypeTelegram ChannelType = "telegram"
+)
+
+// Telegram Bot API url used when channel has none
+const DefaultTelegramAPIURL = "https://api.telegram.org"
+
+// Project notification channel, notified about endpoint state changes
+type NotificationChannel struct {
+	ID        string
+	ProjectId string
+	Name      string
+	// Empty means `ChannelTypeWebhook`
+	Type ChannelType
+	// Disabled channel is not notified
+	Enabled bool
+
+	//* webhook
+
+	// Url payload is posted to. Also incoming webhook url of slack, discord and teams channels
+	WebhookURL string
+	// Key of HMAC-SHA256 payload signature
+	WebhookSecret string
+
+	//* smtp
+
+	SMTPHost string
+	SMTPPort int
+	// Upgrade connection with STARTTLS, sending fails if server doesn't support it
+	SMTPStartTLS bool
+	// Empty means no authentication. Requires STARTTLS unless host is local
+	SMTPUsername string
+	SMTPPassword string
+	// Sender address
+	SMTPFrom       string
+	SMTPRecipients []string
+
+	//* telegram
+
+	// Bot API base url. Empty means `DefaultTelegramAPIURL`
+	TelegramAPIURL   string
+	TelegramBotToken string
+	// Chat id or `@channelusername`
+	TelegramChatID string
+}
+
+// Channel type with default applied
+func (ch *NotificationChannel) ChannelType() ChannelType {
+	if ch.Type == "" {
+		return ChannelTypeWebhook
+	}
+	return ch.Type
+}
+
+func (ch *NotificationChannel) Validate() error {
+	switch ch.ChannelType() {
+	case ChannelTypeWebhook:
+		return validateWebhookChannel(ch)
+	case ChannelTypeSMTP:
+		return validateSMTPChannel(ch)
+	case ChannelTypeSlack, ChannelTypeDiscord, ChannelTypeTeams:
+		return validateChannelURL("webhook url", ch.WebhookURL)
+	case ChannelTypeTelegram:
+		return validateTelegramChannel(ch)
+	default:
+		return fmt.Errorf("unknown channel type: %s", ch.Type)
+	}
+}
+
+// Telegram Bot API base url with default applied
+func (ch *NotificationChannel) TelegramAPI() string {
+	if ch.TelegramAPIURL == "" {
+		return DefaultTelegramAPIURL
+	}
+	return ch.TelegramAPIURL
+}
+
+func validateChannelURL(name, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s must be valid http or https url", name)
+	}
+	return nil
+}
+
+func validateWebhookChannel(ch *NotificationChannel) error {
+	if err := validateChannelURL("webhook url", ch.WebhookURL); err != nil {
+		return err
+	}
+	if ch.WebhookSecret == "" {
+		return errors.New("webhook secret cannot be empty")
+	}
+	return nil
+}
+
+func validateSMTPChannel(ch *NotificationChannel) error {
+	if ch.SMTPHost == "" {
+		return errors.New("smtp host cannot be empty")
+	}
+	if ch.SMTPPort < 1 || ch.SMTPPort > 65535 {
+		return errors.New("smtp port must be in range 1-65535")
+	}
+	if _, err := mail.ParseAddress(ch.SMTPFrom); err != nil {
+		return fmt.Errorf("invalid smtp sender address %q", ch.SMTPFrom)
+	}
+	// plain auth refuses to send password over unencrypted connection to remote host
+	if ch.SMTPUsername != "" && !ch.SMTPStartTLS && !localHost(ch.SMTPHost) {
+		return errors.New("smtp authentication requires STARTTLS")
+	}
+	if len(ch.SMTPRecipients) == 0 {
+		return errors.New("smtp recipients cannot be empty")
+	}
+	for _, r := range ch.SMTPRecipients {
+		if _, err := mail.ParseAddress(r); err != nil {
+			return fmt.Errorf("invalid smtp recipient address %q", r)
+		}
+	}
+	return nil
+}
+
+// Whether host is one plain auth allows without tls
+func localHost(host string) bool {
+	return host == "localhost" || host == "127.0.0.1" || host == "::1"
+}
+
+func validateTelegramChannel(ch *NotificationChannel) error {
+	if err := validateChannelURL("telegram api url", ch.TelegramAPI()); err != nil {
+		return err
+	}
+	if ch.TelegramBotToken == "" {
+		return errors.New("telegram bot token cannot be empty")
+	}
+	if ch.TelegramChatID == "" {
+		return errors.New("telegram chat id cannot be empty")
+	}
+	return nil
+}
+
+// Kind of notification
+type Event string
+
+const (
+	// Endpoint went down
+	EventEndpointDown Event = "endpoint.down"
+	// Endpoint recovered
+	EventEndpointUp Event = "endpoint.up"
+	// Endpoint is still down and nobody acknowledged incident
+	EventIncidentEscalated Event = "incident.escalated"
+)
+
+// Confirmed endpoint state change channels are notified about
+type StateChange struct {
+	Event        Event
+	ProjectId    string
+	EndpointID   string
+	EndpointName string
+	EndpointType CheckType
+	EndpointURL  string
+	From         State
+	To           State
+	At           time.Time
+	// Check that confirmed change, holds failure reason of down event
+	CheckResult
+	// Time endpoint was down, zero for down event. Time since incident opening for escalation
+	Downtime time.Duration
+	// Incident opened, resolved or escalated by change, empty if there is none
+	IncidentID string
+	// Escalation step of incident.escalated event, starts from 1
+	EscalationStep int
+	// Participants on call for escalation step. Their emails replace recipients of smtp channels
+	OnCall []OnCallParticipant
+}
+
+// Notification of status change after `Apply`, nil if change is not notified.
+// Only changes between up (or degraded) and down are notified, so first check of endpoint
+// is silent. Flapping is skipped: endpoint that was up before flapping and is down after it is notified as down.
+// `prev` and `prevSince` are stable state and its start before `Apply`
+func NewStateChange(ep *EndpointInfo, st *EndpointStatus, prev State, prevSince time.Time) *StateChange {
+	var event Event
+	switch {
+	case stateUp(prev) && st.State == StateDown:
+		event = EventEndpointDown
+	case prev == StateDown && stateUp(st.State):
+		event = EventEndpointUp
+	default:
+		return nil
+	}
+
+	change := &StateChange{
+		Event:        event,
+		ProjectId:    st.ProjectId,
+		EndpointID:   ep.ID,
+		EndpointName: ep.Name,
+		EndpointType: ep.CheckType(),
+		EndpointURL:  ep.URL,
+		From:         prev,
+		To:           st.State,
+		At:           st.StateSince,
+		CheckResult:  st.CheckResult,
+	}
+	if event == EventEndpointUp && !prevSince.IsZero() {
+		change.Downtime = st.StateSince.Sub(prevSince)
+	}
+	return change
+}
+
+// Notification of escalation step of open incident. `ep` is current endpoint, nil if it was deleted.
+// State change is incident opening, so endpoint is down from `At` to escalation time `now`
+func NewEscalationChange(inc *Incident, ep *Endpoint, step int, now time.Time) *StateChange {
+	change := &StateChange{
+		Event:          EventIncidentEscalated,
+		ProjectId:      inc.ProjectId,
+		EndpointID:     inc.EndpointID,
+		EndpointName:   inc.EndpointName,
+		From:           StateDown,
+		To:             StateDown,
+		At:             inc.OpenedAt,
+		Downtime:       now.Sub(inc.OpenedAt),
+		IncidentID:     inc.ID,
+		EscalationStep: step,
+	}
+	if ep != nil {
+		change.EndpointName = ep.Name
+		change.EndpointType = ep.CheckType()
+		change.EndpointURL = ep.URL
+		change.CheckResult = ep.CheckResult
+	}
+	return change
+}
+
+// Single attempt to deliver notification to channel
+type Delivery struct {
+	// Shared by all attempts of one notification
+	ID         string
+	ChannelID  string
+	Event      Event
+	EndpointID string
+	// Starts from 1
+	Attempt  int
+	At       time.Time
+	Duration time.Duration
+	// Zero if no response was received
+	StatusCode int
+	// Empty if attempt succeeded
+	Error   string
+	Success bool
+}
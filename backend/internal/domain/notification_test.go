@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestNewStateChange(t *testing.T) {
+	prevSince := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	since := prevSince.Add(90 * time.Minute)
+
+	tests := []struct {
+		name         string
+		prev         State
+		state        State
+		wantEvent    Event
+		wantDowntime time.Duration
+	}{
+		{"up to down", StateUp, StateDown, EventEndpointDown, 0},
+		{"degraded to down", StateDegraded, StateDown, EventEndpointDown, 0},
+		{"down to up", StateDown, StateUp, EventEndpointUp, 90 * time.Minute},
+		{"down to degraded", StateDown, StateDegraded, EventEndpointUp, 90 * time.Minute},
+		{"up to degraded", StateUp, StateDegraded, "", 0},
+		{"first check down", StateUnknown, StateDown, "", 0},
+		{"first check up", StateUnknown, StateUp, "", 0},
+		{"status without stable state", "", StateDown, "", 0},
+		{"entering flapping", StateUp, StateFlapping, "", 0},
+	}
+
+	ep := &EndpointInfo{ID: "ep", Name: "api", URL: "https://example.com"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := &EndpointStatus{
+				ProjectId:    "p",
+				CheckResult:  CheckResult{State: tt.state, Error: "boom"},
+				Confirmation: Confirmation{StateSince: since},
+			}
+			change := NewStateChange(ep, st, tt.prev, prevSince)
+			if tt.wantEvent == "" {
+				if change != nil {
+					t.Fatalf("got %s event, want none", change.Event)
+				}
+				return
+			}
+			if change == nil {
+				t.Fatalf("got no event, want %s", tt.wantEvent)
+			}
+			if change.Event != tt.wantEvent || change.From != tt.prev || change.To != tt.state {
+				t.Fatalf("got %s from %s to %s, want %s from %s to %s", change.Event, change.From, change.To, tt.wantEvent, tt.prev, tt.state)
+			}
+			if change.Downtime != tt.wantDowntime {
+				t.Errorf("got downtime %v, want %v", change.Downtime, tt.wantDowntime)
+			}
+			if !change.At.Equal(since) || change.ProjectId != "p" || change.EndpointID != "ep" || change.Error != "boom" {
+				t.Errorf("change does not describe status: %+v", change)
+			}
+		})
+	}
+}
+
+func TestValidateSMTPAuth(t *testing.T) {
+	tests := []struct {
+		host     string
+		startTLS bool
+		wantErr  bool
+	}{
+		{"smtp.example.com", true, false},
+		{"smtp.example.com", false, true},
+		{"localhost", false, false},
+		{"127.0.0.1", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host+"/starttls="+strconv.FormatBool(tt.startTLS), func(t *testing.T) {
+			ch := &NotificationChannel{
+				Type:           ChannelTypeSMTP,
+				SMTPHost:       tt.host,
+				SMTPPort:       587,
+				SMTPStartTLS:   tt.startTLS,
+				SMTPUsername:   "user",
+				SMTPFrom:       "monitor@example.com",
+				SMTPRecipients: []string{"ops@example.com"},
+			}
+			if err := ch.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("got err %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
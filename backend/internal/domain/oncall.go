@@ -0,0 +1,214 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"time"
+	// time zones of schedules don't depend on tz database of host
+	_ "time/tzdata"
+)
+
+// How often on-call participants hand off
+type RotationType string
+
+const (
+	// Next participant takes over every day at handoff time
+	RotationDaily RotationType = "daily"
+	// Next participant takes over every week at handoff day and time
+	RotationWeekly RotationType = "weekly"
+)
+
+const (
+	// Max participants of on-call rotation
+	MaxScheduleParticipants = 50
+	// Max overrides of schedule that didn't end yet
+	MaxScheduleOverrides = 100
+)
+
+// Person taking part in on-call rotation
+type OnCallParticipant struct {
+	Name string
+	// Recipient of smtp channels while participant is on call. Empty if participant has no email
+	Email string
+}
+
+func (p *OnCallParticipant) Validate() error {
+	if p.Name == "" {
+		return errors.New("participant name cannot be empty")
+	}
+	if p.Email != "" {
+		if _, err := mail.ParseAddress(p.Email); err != nil {
+			return fmt.Errorf("invalid participant email %q", p.Email)
+		}
+	}
+	return nil
+}
+
+// Project on-call schedule. Participants take turns in order, handing off at handoff time
+// in schedule time zone, so shifts follow daylight saving time changes
+type OnCallSchedule struct {
+	ID        string
+	ProjectId string
+	Name      string
+	// IANA time zone name. Empty means UTC
+	Timezone string
+	Rotation RotationType
+	// Time since midnight participants hand off at
+	HandoffTime time.Duration
+	// Day weekly rotation hands off on
+	HandoffWeekday time.Weekday
+	// First participant is on call from start until next handoff, nobody is on call before start
+	Start        time.Time
+	Participants []OnCallParticipant
+}
+
+func (s *OnCallSchedule) Validate() error {
+	if s.Name == "" {
+		return errors.New("name cannot be empty")
+	}
+	if _, err := s.Location(); err != nil {
+		return fmt.Errorf("unknown time zone: %s", s.Timezone)
+	}
+	if s.Rotation != RotationDaily && s.Rotation != RotationWeekly {
+		return fmt.Errorf("unknown rotation: %s", s.Rotation)
+	}
+	if s.HandoffTime < 0 || s.HandoffTime >= 24*time.Hour || s.HandoffTime%time.Minute != 0 {
+		return errors.New("handoff time must be whole minutes within a day")
+	}
+	if s.HandoffWeekday < time.Sunday || s.HandoffWeekday > time.Saturday {
+		return errors.New("invalid handoff weekday")
+	}
+	if s.Start.IsZero() {
+		return errors.New("start cannot be empty")
+	}
+	if len(s.Participants) == 0 {
+		return errors.New("participants cannot be empty")
+	}
+	if len(s.Participants) > MaxScheduleParticipants {
+		return fmt.Errorf("schedule cannot have more than %d participants", MaxScheduleParticipants)
+	}
+	for i := range s.Participants {
+		if err := s.Participants[i].Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Time zone of schedule
+func (s *OnCallSchedule) Location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(s.Timezone)
+}
+
+// Temporary replacement of rotation participant
+type OnCallOverride struct {
+	ID          string
+	Start       time.Time
+	End         time.Time
+	Participant OnCallParticipant
+}
+
+func (o *OnCallOverride) Validate() error {
+	if o.Start.IsZero() || o.End.IsZero() {
+		return errors.New("start and end cannot be empty")
+	}
+	if !o.End.After(o.Start) {
+		return errors.New("end must be after start")
+	}
+	return o.Participant.Validate()
+}
+
+// Whether override is in effect at `t`
+func (o *OnCallOverride) Active(t time.Time) bool {
+	return !t.Before(o.Start) && t.Before(o.End)
+}
+
+// Participant on call and bounds of its shift
+type OnCallShift struct {
+	Participant OnCallParticipant
+	Start       time.Time
+	End         time.Time
+	// Override that replaced rotation participant, nil if there is none
+	Override *OnCallOverride
+}
+
+// Shift at `t`, nil if nobody is on call. Override active at `t` replaces rotation participant,
+// of overlapping overrides one that started last wins
+func (s *OnCallSchedule) OnCallAt(t time.Time, overrides []*OnCallOverride) *OnCallShift {
+	var override *OnCallOverride
+	for _, o := range overrides {
+		if o.Active(t) && (override == nil || o.Start.After(override.Start)) {
+			override = o
+		}
+	}
+	if override != nil {
+		return &OnCallShift{
+			Participant: override.Participant,
+			Start:       override.Start,
+			End:         override.End,
+			Override:    override,
+		}
+	}
+	return s.RotationAt(t)
+}
+
+// Rotation shift at `t` without overrides, nil before start of schedule
+func (s *OnCallSchedule) RotationAt(t time.Time) *OnCallShift {
+	if t.Before(s.Start) || len(s.Participants) == 0 {
+		return nil
+	}
+	loc, err := s.Location()
+	if err != nil {
+		return nil
+	}
+
+	period := 1
+	if s.Rotation == RotationWeekly {
+		period = 7
+	}
+	first := s.lastHandoff(s.Start, loc, period)
+	last := s.lastHandoff(t, loc, period)
+	// days are counted by calendar date, so shifts that span dst changes are not off by an hour
+	shift := (civilDays(last, loc) - civilDays(first, loc)) / period
+
+	y, m, d := last.In(loc).Date()
+	start := last
+	if shift == 0 {
+		start = s.Start
+	}
+	return &OnCallShift{
+		Participant: s.Participants[shift%len(s.Participants)],
+		Start:       start,
+		End:         s.handoffOn(y, m, d+period, loc),
+	}
+}
+
+// Latest handoff at or before `t`
+func (s *OnCallSchedule) lastHandoff(t time.Time, loc *time.Location, period int) time.Time {
+	local := t.In(loc)
+	y, m, d := local.Date()
+	if s.Rotation == RotationWeekly {
+		d -= (int(local.Weekday()) - int(s.HandoffWeekday) + 7) % 7
+	}
+	h := s.handoffOn(y, m, d, loc)
+	if h.After(t) {
+		h = s.handoffOn(y, m, d-period, loc)
+	}
+	return h
+}
+
+// Handoff on given date, day out of month range is normalized
+func (s *OnCallSchedule) handoffOn(y int, m time.Month, d int, loc *time.Location) time.Time {
+	minutes := int(s.HandoffTime / time.Minute)
+	return time.Date(y, m, d, minutes/60, minutes%60, 0, 0, loc)
+}
+
+// Days since unix epoch of calendar date of `t` in `loc`
+func civilDays(t time.Time, loc *time.Location) int {
+	y, m, d := t.In(loc).Date()
+	return int(time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / 86400)
+}
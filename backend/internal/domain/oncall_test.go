@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+// Parse `2006-01-02 15:04` local time of time zone
+func localTime(t *testing.T, tz, value string) time.Time {
+	t.Helper()
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := time.ParseInLocation("2006-01-02 15:04", value, loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func TestRotationAt(t *testing.T) {
+	participants := []OnCallParticipant{{Name: "A"}, {Name: "B"}, {Name: "C"}}
+	berlinWeekly := &OnCallSchedule{
+		Timezone:       "Europe/Berlin",
+		Rotation:       RotationWeekly,
+		HandoffTime:    9 * time.Hour,
+		HandoffWeekday: time.Monday,
+		Start:          localTime(t, "Europe/Berlin", "2024-03-11 09:00"),
+		Participants:   participants,
+	}
+	newYorkDaily := &OnCallSchedule{
+		Timezone:     "America/New_York",
+		Rotation:     RotationDaily,
+		HandoffTime:  18 * time.Hour,
+		Start:        localTime(t, "America/New_York", "2024-11-01 10:00"),
+		Participants: participants[:2],
+	}
+
+	tests := []struct {
+		name      string
+		schedule  *OnCallSchedule
+		at        string
+		want      string
+		wantStart string
+		wantEnd   string
+	}{
+		{"berlin first week", berlinWeekly, "2024-03-12 12:00", "A", "2024-03-11 09:00", "2024-03-18 09:00"},
+		// dst starts on 2024-03-31, shift is an hour shorter
+		{"berlin week across dst start", berlinWeekly, "2024-04-01 08:59", "C", "2024-03-25 09:00", "2024-04-01 09:00"},
+		{"berlin handoff after dst start", berlinWeekly, "2024-04-01 09:00", "A", "2024-04-01 09:00", "2024-04-08 09:00"},
+		{"berlin handoff weekday", berlinWeekly, "2024-04-14 23:00", "B", "2024-04-08 09:00", "2024-04-15 09:00"},
+		{"new york starts mid shift", newYorkDaily, "2024-11-01 12:00", "A", "2024-11-01 10:00", "2024-11-01 18:00"},
+		{"new york next day", newYorkDaily, "2024-11-01 18:00", "B", "2024-11-01 18:00", "2024-11-02 18:00"},
+		// dst ends on 2024-11-03, shift is an hour longer
+		{"new york day across dst end", newYorkDaily, "2024-11-03 12:00", "A", "2024-11-02 18:00", "2024-11-03 18:00"},
+		{"new york after dst end", newYorkDaily, "2024-11-03 20:00", "B", "2024-11-03 18:00", "2024-11-04 18:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shift := tt.schedule.RotationAt(localTime(t, tt.schedule.Timezone, tt.at))
+			if shift == nil {
+				t.Fatal("nobody is on call")
+			}
+			start := localTime(t, tt.schedule.Timezone, tt.wantStart)
+			end := localTime(t, tt.schedule.Timezone, tt.wantEnd)
+			if shift.Participant.Name != tt.want || !shift.Start.Equal(start) || !shift.End.Equal(end) {
+				t.Fatalf("got %s from %v to %v, want %s from %v to %v",
+					shift.Participant.Name, shift.Start, shift.End, tt.want, start, end)
+			}
+		})
+	}
+
+	t.Run("before start", func(t *testing.T) {
+		if shift := berlinWeekly.RotationAt(berlinWeekly.Start.Add(-time.Minute)); shift != nil {
+			t.Fatalf("got %s on call before schedule start", shift.Participant.Name)
+		}
+	})
+}
+
+func TestOnCallAtOverrides(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	schedule := &OnCallSchedule{
+		Rotation:     RotationDaily,
+		Start:        start,
+		Participants: []OnCallParticipant{{Name: "A"}, {Name: "B"}},
+	}
+	first := &OnCallOverride{Start: start.Add(2 * time.Hour), End: start.Add(6 * time.Hour), Participant: OnCallParticipant{Name: "X"}}
+	second := &OnCallOverride{Start: start.Add(4 * time.Hour), End: start.Add(5 * time.Hour), Participant: OnCallParticipant{Name: "Y"}}
+	overrides := []*OnCallOverride{second, first}
+
+	tests := []struct {
+		name         string
+		at           time.Duration
+		want         string
+		wantOverride *OnCallOverride
+	}{
+		{"rotation before override", time.Hour, "A", nil},
+		{"override wins", 3 * time.Hour, "X", first},
+		{"later override wins", 4 * time.Hour, "Y", second},
+		{"earlier override after later one ended", 5 * time.Hour, "X", first},
+		{"rotation after override end", 6 * time.Hour, "A", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shift := schedule.OnCallAt(start.Add(tt.at), overrides)
+			if shift == nil || shift.Participant.Name != tt.want || shift.Override != tt.wantOverride {
+				t.Fatalf("got shift %+v, want %s", shift, tt.want)
+			}
+		})
+	}
+}
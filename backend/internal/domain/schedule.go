@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Bounds of per-endpoint check schedule
+const (
+	MinCheckInterval = 10 * time.Second
+	MaxCheckInterval = 24 * time.Hour
+	MaxCheckRetries  = 5
+	// Fast re-checks while endpoint state is being confirmed
+	MinRecheckInterval = time.Second
+)
+
+// Check interval of endpoint, `def` if endpoint has no own interval
+func (ep *EndpointInfo) CheckInterval(def time.Duration) time.Duration {
+	if ep.Interval > 0 {
+		return ep.Interval
+	}
+	return def
+}
+
+// Check timeout of endpoint, `def` if endpoint has no own timeout
+func (ep *EndpointInfo) CheckTimeout(def time.Duration) time.Duration {
+	if ep.Timeout > 0 {
+		return ep.Timeout
+	}
+	return def
+}
+
+func validateSchedule(ep *EndpointInfo) error {
+	if ep.Interval != 0 && (ep.Interval < MinCheckInterval || ep.Interval > MaxCheckInterval) {
+		return fmt.Errorf("interval must be in range %s-%s", MinCheckInterval, MaxCheckInterval)
+	}
+	if ep.Timeout < 0 {
+		return errors.New("timeout cannot be negative")
+	}
+	if ep.Interval != 0 && ep.Timeout > ep.Interval {
+		return errors.New("timeout cannot be greater than interval")
+	}
+	if ep.Retries < 0 || ep.Retries > MaxCheckRetries {
+		return fmt.Errorf("retries must be in range 0-%d", MaxCheckRetries)
+	}
+	if ep.FailureThreshold < 0 || ep.FailureThreshold > MaxConfirmationThreshold ||
+		ep.RecoveryThreshold < 0 || ep.RecoveryThreshold > MaxConfirmationThreshold {
+		return fmt.Errorf("failure and recovery thresholds must be in range 0-%d", MaxConfirmationThreshold)
+	}
+	if ep.RecheckInterval != 0 && (ep.RecheckInterval < MinRecheckInterval || ep.RecheckInterval >= ep.CheckInterval(MaxCheckInterval)) {
+		return fmt.Errorf("recheck interval must be at least %s and less than interval", MinRecheckInterval)
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"errors"
+	"net"
+	"strings"
+)
+
+func validateTCPCheck(ep *EndpointInfo) error {
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(ep.URL, "tcp://"))
+	if err != nil || host == "" || port == "" {
+		return errors.New("tcp check url must be in host:port format")
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"time"
+)
+
+// Days before certificate expiry when endpoint becomes degraded, if endpoint has no own threshold
+const DefaultTLSExpiryThresholdDays = 14
+
+// Server certificate recorded during tls handshake
+type CertInfo struct {
+	NotAfter time.Time
+	Issuer   string
+	Subject  string
+	// DNS names and IP addresses
+	SANs       []string
+	ChainValid bool
+	// Chain verification error, empty if chain is valid
+	ChainError string
+}
+
+// Whether certificate expires within `threshold` from `now`
+func (c *CertInfo) ExpiresWithin(now time.Time, threshold time.Duration) bool {
+	return c.NotAfter.Before(now.Add(threshold))
+}
+
+// Expiry threshold with default applied
+func (ep *EndpointInfo) TLSExpiryThreshold() time.Duration {
+	days := ep.TLSExpiryThresholdDays
+	if days <= 0 {
+		days = DefaultTLSExpiryThresholdDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+func validateTLSCheck(ep *EndpointInfo) error {
+	addr := strings.TrimPrefix(ep.URL, "tls://")
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		// port is optional
+		if strings.ContainsAny(addr, "/ ") || addr == "" {
+			return errors.New("tls check url must be in host[:port] format")
+		}
+	}
+	return nil
+}
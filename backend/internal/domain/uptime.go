@@ -0,0 +1,98 @@
+package domain
+
+import "time"
+
+// SLO target used when endpoint has none
+const DefaultSLOTarget = 99.9
+
+// Uptime of endpoint (or project) over time window, computed from check history
+type Uptime struct {
+	From        time.Time
+	To          time.Time
+	TotalChecks int64
+	UpChecks    int64
+	// Percentage of successful checks. 100 if there were no checks
+	UptimePercent float64
+	// Target uptime percentage
+	SLOTarget float64
+	// Downtime allowed by SLO over the window
+	ErrorBudget time.Duration
+	// Downtime estimated as share of failed checks over the window
+	Downtime time.Duration
+	// Percentage of error budget consumed. Can be greater than 100
+	ErrorBudgetConsumed float64
+}
+
+// Compute uptime of checks within [from, to] against `sloTarget` percentage
+func ComputeUptime(checks []*EndpointCheck, from, to time.Time, sloTarget float64) *Uptime {
+	u := NewUptime(from, to, sloTarget)
+	for _, c := range checks {
+		u.Add(c)
+	}
+	return u
+}
+
+// Uptime of window without checks, filled by `Add()`
+func NewUptime(from, to time.Time, sloTarget float64) *Uptime {
+	if sloTarget <= 0 {
+		sloTarget = DefaultSLOTarget
+	}
+	u := &Uptime{
+		From:      from,
+		To:        to,
+		SLOTarget: sloTarget,
+	}
+	u.calc()
+	return u
+}
+
+// Count check, so history can be streamed instead of loaded at once.
+// Check outside of window is ignored
+func (u *Uptime) Add(c *EndpointCheck) {
+	if c.CheckedAt.Before(u.From) || c.CheckedAt.After(u.To) {
+		return
+	}
+	u.TotalChecks++
+	if c.Up() {
+		u.UpChecks++
+	}
+	u.calc()
+}
+
+// Merge uptimes of several endpoints over the same window into one.
+// SLO target of result is the strictest of merged ones
+func MergeUptimes(from, to time.Time, uptimes []*Uptime) *Uptime {
+	u := &Uptime{
+		From: from,
+		To:   to,
+	}
+	for _, v := range uptimes {
+		u.TotalChecks += v.TotalChecks
+		u.UpChecks += v.UpChecks
+		if v.SLOTarget > u.SLOTarget {
+			u.SLOTarget = v.SLOTarget
+		}
+	}
+	if u.SLOTarget == 0 {
+		u.SLOTarget = DefaultSLOTarget
+	}
+	u.calc()
+	return u
+}
+
+func (u *Uptime) calc() {
+	window := u.To.Sub(u.From)
+
+	downRatio := 0.0
+	if u.TotalChecks > 0 {
+		downRatio = float64(u.TotalChecks-u.UpChecks) / float64(u.TotalChecks)
+	}
+	budgetRatio := 1 - u.SLOTarget/100
+
+	u.UptimePercent = (1 - downRatio) * 100
+	u.ErrorBudget = time.Duration(float64(window) * budgetRatio)
+	u.Downtime = time.Duration(float64(window) * downRatio)
+	if budgetRatio > 0 {
+		u.ErrorBudgetConsumed = downRatio / budgetRatio * 100
+	}
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestComputeUptime(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(100 * time.Hour)
+	// checks of given states, one per hour from `from + start`
+	checks := func(start time.Duration, states ...State) []*EndpointCheck {
+		res := make([]*EndpointCheck, len(states))
+		for i, s := range states {
+			res[i] = &EndpointCheck{
+				CheckedAt:   from.Add(start + time.Duration(i)*time.Hour),
+				CheckResult: CheckResult{State: s},
+			}
+		}
+		return res
+	}
+	upDown := func(up, down int) []State {
+		res := make([]State, 0, up+down)
+		for range up {
+			res = append(res, StateUp)
+		}
+		for range down {
+			res = append(res, StateDown)
+		}
+		return res
+	}
+
+	tests := []struct {
+		name         string
+		checks       []*EndpointCheck
+		slo          float64
+		wantTotal    int64
+		wantUp       int64
+		wantPercent  float64
+		wantSLO      float64
+		wantDowntime time.Duration
+		wantConsumed float64
+	}{
+		{"empty window", nil, 99, 0, 0, 100, 99, 0, 0},
+		{"all up", checks(0, upDown(10, 0)...), 99, 10, 10, 100, 99, 0, 0},
+		{"degraded counts as up", checks(0, StateDegraded, StateDown), 50, 2, 1, 50, 50, 50 * time.Hour, 100},
+		{"one percent down", checks(0, upDown(99, 1)...), 99, 100, 99, 99, 99, time.Hour, 100},
+		{"budget overspent", checks(0, upDown(98, 2)...), 99, 100, 98, 98, 99, 2 * time.Hour, 200},
+		{"default slo", checks(0, upDown(99, 1)...), 0, 100, 99, 99, DefaultSLOTarget, time.Hour, 1000},
+		// gap without checks doesn't count as downtime, downtime is share of failed checks
+		{"gap in history", checks(90*time.Hour, upDown(3, 1)...), 99, 4, 3, 75, 99, 25 * time.Hour, 2500},
+		{"checks outside window", append(checks(-time.Hour, StateDown), checks(100*time.Hour, StateUp, StateDown)...), 99, 1, 1, 100, 99, 0, 0},
+		{"slo of 100 has no budget", checks(0, StateDown), 100, 1, 0, 0, 100, 100 * time.Hour, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := ComputeUptime(tt.checks, from, to, tt.slo)
+			if u.TotalChecks != tt.wantTotal || u.UpChecks != tt.wantUp || u.SLOTarget != tt.wantSLO {
+				t.Fatalf("got %d/%d checks up, slo %v; want %d/%d, slo %v", u.UpChecks, u.TotalChecks, u.SLOTarget, tt.wantUp, tt.wantTotal, tt.wantSLO)
+			}
+			if !almostEqual(u.UptimePercent, tt.wantPercent) {
+				t.Errorf("got uptime %v%%, want %v%%", u.UptimePercent, tt.wantPercent)
+			}
+			if (u.Downtime - tt.wantDowntime).Abs() > time.Millisecond {
+				t.Errorf("got downtime %v, want %v", u.Downtime, tt.wantDowntime)
+			}
+			if !almostEqual(u.ErrorBudgetConsumed, tt.wantConsumed) {
+				t.Errorf("got error budget consumed %v%%, want %v%%", u.ErrorBudgetConsumed, tt.wantConsumed)
+			}
+			if wantBudget := time.Duration(float64(to.Sub(from)) * (1 - tt.wantSLO/100)); (u.ErrorBudget - wantBudget).Abs() > time.Millisecond {
+				t.Errorf("got error budget %v, want %v", u.ErrorBudget, wantBudget)
+			}
+		})
+	}
+}
+
+func TestMergeUptimes(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	a := &Uptime{TotalChecks: 10, UpChecks: 10, SLOTarget: 99}
+	b := &Uptime{TotalChecks: 30, UpChecks: 20, SLOTarget: 99.5}
+
+	u := MergeUptimes(from, to, []*Uptime{a, b})
+	if u.TotalChecks != 40 || u.UpChecks != 30 || u.SLOTarget != 99.5 || !almostEqual(u.UptimePercent, 75) {
+		t.Fatalf("got %d/%d checks up, uptime %v%%, slo %v; want 30/40, 75%%, 99.5", u.UpChecks, u.TotalChecks, u.UptimePercent, u.SLOTarget)
+	}
+	if empty := MergeUptimes(from, to, nil); empty.SLOTarget != DefaultSLOTarget || empty.UptimePercent != 100 {
+		t.Errorf("got empty merge slo %v, uptime %v%%", empty.SLOTarget, empty.UptimePercent)
+	}
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
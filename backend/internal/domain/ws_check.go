@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+func validateWSCheck(ep *EndpointInfo) error {
+	u, err := url.Parse(ep.URL)
+	if err != nil || (u.Scheme != "ws" && u.Scheme != "wss") || u.Host == "" {
+		return errors.New("websocket check url must be absolute ws or wss url")
+	}
+	for _, h := range ep.Headers {
+		if strings.TrimSpace(h.Name) == "" {
+			return errors.New("header name cannot be empty")
+		}
+	}
+	if err := ep.CompileWSPattern(); err != nil {
+		return errors.New("websocket expect pattern must be valid regular expression")
+	}
+	return nil
+}
+
+// Compile websocket expect pattern once, so checks don't recompile it.
+// Done by `Validate`, endpoints loaded from storage are compiled on load
+func (ep *EndpointInfo) CompileWSPattern() error {
+	ep.wsPattern = nil
+	if ep.WSExpectPattern == "" {
+		return nil
+	}
+	re, err := regexp.Compile(ep.WSExpectPattern)
+	if err != nil {
+		return err
+	}
+	ep.wsPattern = re
+	return nil
+}
+
+// Compiled websocket expect pattern, nil if pattern is empty or was not compiled
+func (ep *EndpointInfo) WSPattern() *regexp.Regexp {
+	return ep.wsPattern
+}
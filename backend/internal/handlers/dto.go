@@ -2,34 +2,406 @@ package handlers
 
 //* Request
 type CreateEndpointRequest struct {
-	Name string `json:"name"`
-	URL  string `json:"url"`
+	EndpointConfig
 }
 
+// Fields missing in request keep their current values
 type UpdateEndpointInfoRequest struct {
+	EndpointConfig
+}
+
+// Endpoint configuration, shared by requests and responses
+type EndpointConfig struct {
+	Name string `json:"name"`
+	// http (default), tcp, dns, tls, grpc, websocket or heartbeat
+	Type string `json:"type"`
+	// url for http and websocket checks, host:port for tcp and grpc checks, hostname for dns check,
+	// host[:port] for tls check, not used by heartbeat check
+	URL       string  `json:"url"`
+	SLOTarget float64 `json:"slo_target"`
+	// escalation of unacknowledged incidents, empty means no escalation
+	EscalationPolicyID string `json:"escalation_policy_id"`
+	// schedule, zero means monitor default
+	IntervalSec int64 `json:"interval_sec"`
+	TimeoutMs   int64 `json:"timeout_ms"`
+	Retries     int   `json:"retries"`
+	// consecutive checks to confirm state change, zero means 1
+	FailureThreshold   int   `json:"failure_threshold"`
+	RecoveryThreshold  int   `json:"recovery_threshold"`
+	RecheckIntervalSec int64 `json:"recheck_interval_sec"`
+	// http check, headers are also used as grpc check metadata and websocket handshake headers
+	Method           string              `json:"method"`
+	Headers          []*HeaderRequest    `json:"headers"`
+	Body             string              `json:"body"`
+	ExpectedStatuses string              `json:"expected_statuses"`
+	RedirectPolicy   string              `json:"redirect_policy"`
+	Assertions       []*AssertionRequest `json:"assertions"`
+	// tcp check
+	TCPPayload      string `json:"tcp_payload"`
+	TCPExpectBanner string `json:"tcp_expect_banner"`
+	// dns check
+	DNSRecordType string   `json:"dns_record_type"`
+	DNSResolver   string   `json:"dns_resolver"`
+	DNSExpected   []string `json:"dns_expected"`
+	// grpc check
+	GRPCService string `json:"grpc_service"`
+	GRPCTLS     bool   `json:"grpc_tls"`
+	// websocket check
+	WSMessage       string `json:"ws_message"`
+	WSExpectPattern string `json:"ws_expect_pattern"`
+	// heartbeat check
+	HeartbeatPeriodSec int64 `json:"heartbeat_period_sec"`
+	HeartbeatGraceSec  int64 `json:"heartbeat_grace_sec"`
+	// tls and https checks. Zero means default (14 days)
+	TLSExpiryThresholdDays int `json:"tls_expiry_threshold_days"`
+}
+
+type AssertionRequest struct {
+	// body_contains, body_not_contains, body_regex, json_path or header
+	Type string `json:"type"`
+	// JSONPath expression or header name
+	Target string `json:"target,omitempty"`
+	// eq, ne, lt, lte, gt, gte or exists. Only for json_path
+	Operator string `json:"operator,omitempty"`
+	Value    string `json:"value"`
+}
+
+type HeaderRequest struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type CreateChannelRequest struct {
+	ChannelConfig
+}
+
+// Fields missing in request keep their current values
+type UpdateChannelRequest struct {
+	ChannelConfig
+}
+
+// Notification channel configuration, shared by requests and responses.
+// Responses leave secrets out
+type ChannelConfig struct {
 	Name string `json:"name"`
-	URL  string `json:"url"`
+	// webhook (default), smtp, slack, discord, teams or telegram
+	Type string `json:"type"`
+	// Defaults to true on creation
+	Enabled bool `json:"enabled"`
+	// webhook channel, secret is generated if empty and returned once. Url is also used by slack, discord and teams channels
+	WebhookURL    string `json:"webhook_url"`
+	WebhookSecret string `json:"webhook_secret,omitempty"`
+	// smtp channel, empty username means no authentication
+	SMTPHost       string   `json:"smtp_host"`
+	SMTPPort       int      `json:"smtp_port"`
+	SMTPStartTLS   bool     `json:"smtp_starttls"`
+	SMTPUsername   string   `json:"smtp_username"`
+	SMTPPassword   string   `json:"smtp_password,omitempty"`
+	SMTPFrom       string   `json:"smtp_from"`
+	SMTPRecipients []string `json:"smtp_recipients"`
+	// telegram channel, empty api url means https://api.telegram.org
+	TelegramAPIURL   string `json:"telegram_api_url"`
+	TelegramBotToken string `json:"telegram_bot_token,omitempty"`
+	TelegramChatID   string `json:"telegram_chat_id"`
+}
+
+type CreateEscalationPolicyRequest struct {
+	EscalationPolicyConfig
+}
+
+// Fields missing in request keep their current values
+type UpdateEscalationPolicyRequest struct {
+	EscalationPolicyConfig
+}
+
+// Escalation policy configuration, shared by requests and responses
+type EscalationPolicyConfig struct {
+	Name string `json:"name"`
+	// run in order, then again from first one until incident is acknowledged or resolved
+	Steps []*EscalationStepRequest `json:"steps"`
+}
+
+type EscalationStepRequest struct {
+	// time after previous step, after incident opening for first step
+	DelaySec   int64    `json:"delay_sec"`
+	ChannelIDs []string `json:"channel_ids"`
+	// participants on call in these schedules are notified through step channels
+	ScheduleIDs []string `json:"schedule_ids,omitempty"`
+}
+
+type CreateScheduleRequest struct {
+	ScheduleConfig
+}
+
+// Fields missing in request keep their current values
+type UpdateScheduleRequest struct {
+	ScheduleConfig
+}
+
+// On-call schedule configuration, shared by requests and responses
+type ScheduleConfig struct {
+	Name string `json:"name"`
+	// IANA time zone name, e.g. Europe/Berlin, empty means UTC
+	Timezone string `json:"timezone"`
+	// daily or weekly, weekly by default
+	Rotation string `json:"rotation"`
+	// HH:MM in schedule time zone, 09:00 by default
+	HandoffTime string `json:"handoff_time"`
+	// weekday of weekly rotation handoff, monday by default
+	HandoffDay string `json:"handoff_day"`
+	// RFC3339, first participant is on call from start. Creation time by default
+	Start        string                `json:"start"`
+	Participants []*ParticipantRequest `json:"participants"`
+}
+
+type ParticipantRequest struct {
+	Name string `json:"name"`
+	// smtp channels email participant instead of their recipients, may be omitted
+	Email string `json:"email,omitempty"`
+}
+
+// Temporary replacement of rotation participant
+type OverrideRequest struct {
+	// RFC3339
+	Start string `json:"start"`
+	End   string `json:"end"`
+	ParticipantRequest
+}
+
+// Body of incident acknowledge and resolve requests, may be omitted
+type IncidentActionRequest struct {
+	By string `json:"by"`
+}
+
+type CommentRequest struct {
+	Author string `json:"author"`
+	Text   string `json:"text"`
 }
 
 //* Response
 type EndpointResponse struct {
-	ID           string `json:"id"`
-	Name         string `json:"name"`
-	URL          string `json:"url"`
-	Status       string `json:"status"`
-	LastChecked  string `json:"last_checked_at"`
-	ResponseTime string `json:"response_time"`
+	ID string `json:"id"`
+	EndpointConfig
+	// Heartbeat check only, signals are sent to /api/heartbeat/{token}
+	HeartbeatToken string `json:"heartbeat_token,omitempty"`
+	LastChecked    string `json:"last_checked_at"`
+	CheckResultResponse
+	ConfirmationResponse
+	FlappingResponse
+	// Active incident of endpoint
+	IncidentID string `json:"incident_id,omitempty"`
 }
 
 type EndpointInfoResponse struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
-	URL  string `json:"url"`
+	ID string `json:"id"`
+	EndpointConfig
+	HeartbeatToken string `json:"heartbeat_token,omitempty"`
 }
 
 type EndpointStatusResponse struct {
+	ID          string `json:"id"`
+	LastChecked string `json:"last_checked_at"`
+	CheckResultResponse
+	ConfirmationResponse
+	FlappingResponse
+	IncidentID string `json:"incident_id,omitempty"`
+}
+
+// `state` of endpoint is confirmed state, `check_state` is state of last check
+type ConfirmationResponse struct {
+	CheckState           string `json:"check_state"`
+	ConsecutiveFailures  int    `json:"consecutive_failures"`
+	ConsecutiveSuccesses int    `json:"consecutive_successes"`
+	StateSince           string `json:"state_since"`
+	// Last checks disagree with state, but are not enough to change it yet
+	Confirming bool `json:"confirming"`
+}
+
+type FlappingResponse struct {
+	Flapping bool `json:"flapping"`
+	// Weighted percentage of state changes among recent checks
+	FlapScore float64 `json:"flap_score"`
+}
+
+type CheckResultResponse struct {
+	State      string  `json:"state"`
+	StatusCode int     `json:"status_code,omitempty"`
+	ErrorClass string  `json:"error_class,omitempty"`
+	Error      string  `json:"error,omitempty"`
+	DurationMs float64 `json:"duration_ms"`
+	// websocket check
+	HandshakeMs float64 `json:"handshake_ms,omitempty"`
+	RoundTripMs float64 `json:"round_trip_ms,omitempty"`
+	// Missing if check did no tls handshake
+	Certificate *CertificateResponse `json:"certificate,omitempty"`
+}
+
+type CertificateResponse struct {
+	NotAfter   string   `json:"not_after"`
+	Issuer     string   `json:"issuer"`
+	Subject    string   `json:"subject"`
+	SANs       []string `json:"sans"`
+	ChainValid bool     `json:"chain_valid"`
+	ChainError string   `json:"chain_error,omitempty"`
+	// Days until expiry, negative if certificate is expired
+	DaysLeft int `json:"days_left"`
+}
+
+type EndpointCheckResponse struct {
+	CheckedAt string `json:"checked_at"`
+	CheckResultResponse
+}
+
+type EndpointHistoryResponse struct {
+	Total  int64                    `json:"total"`
+	Limit  int64                    `json:"limit"`
+	Offset int64                    `json:"offset"`
+	Checks []*EndpointCheckResponse `json:"checks"`
+}
+
+type UptimeResponse struct {
+	From                string  `json:"from"`
+	To                  string  `json:"to"`
+	TotalChecks         int64   `json:"total_checks"`
+	UpChecks            int64   `json:"up_checks"`
+	UptimePercent       float64 `json:"uptime_percent"`
+	SLOTarget           float64 `json:"slo_target"`
+	ErrorBudgetSec      float64 `json:"error_budget_sec"`
+	DowntimeSec         float64 `json:"downtime_sec"`
+	ErrorBudgetConsumed float64 `json:"error_budget_consumed_percent"`
+}
+
+type EndpointUptimeResponse struct {
+	ID     string          `json:"id"`
+	Name   string          `json:"name"`
+	Uptime *UptimeResponse `json:"uptime"`
+}
+
+type ProjectUptimeResponse struct {
+	Uptime    *UptimeResponse           `json:"uptime"`
+	Endpoints []*EndpointUptimeResponse `json:"endpoints"`
+}
+
+type LatencyBucketResponse struct {
+	// Zero means +Inf
+	UpperBoundMs float64 `json:"le_ms"`
+	Count        int64   `json:"count"`
+}
+
+type LatencyResponse struct {
+	From      string                   `json:"from"`
+	To        string                   `json:"to"`
+	Count     int64                    `json:"count"`
+	MinMs     float64                  `json:"min_ms"`
+	MaxMs     float64                  `json:"max_ms"`
+	MeanMs    float64                  `json:"mean_ms"`
+	P50Ms     float64                  `json:"p50_ms"`
+	P90Ms     float64                  `json:"p90_ms"`
+	P95Ms     float64                  `json:"p95_ms"`
+	P99Ms     float64                  `json:"p99_ms"`
+	Histogram []*LatencyBucketResponse `json:"histogram"`
+}
+
+type ChannelResponse struct {
+	ID string `json:"id"`
+	ChannelConfig
+	// whether secrets are set, their values are not returned
+	HasWebhookSecret    bool `json:"has_webhook_secret"`
+	HasSMTPPassword     bool `json:"has_smtp_password"`
+	HasTelegramBotToken bool `json:"has_telegram_bot_token"`
+}
+
+type EscalationPolicyResponse struct {
+	ID string `json:"id"`
+	EscalationPolicyConfig
+}
+
+type ScheduleResponse struct {
+	ID string `json:"id"`
+	ScheduleConfig
+}
+
+type OverrideResponse struct {
+	ID string `json:"id"`
+	OverrideRequest
+}
+
+type OnCallResponse struct {
+	ScheduleID string `json:"schedule_id"`
+	At         string `json:"at"`
+	// Missing if nobody is on call
+	Participant *ParticipantRequest `json:"participant,omitempty"`
+	ShiftStart  string              `json:"shift_start,omitempty"`
+	ShiftEnd    string              `json:"shift_end,omitempty"`
+	// Missing if participant is not from override
+	OverrideID string `json:"override_id,omitempty"`
+}
+
+// Single delivery attempt of notification
+type DeliveryResponse struct {
+	// Shared by all attempts of one notification
+	ID         string  `json:"id"`
+	Event      string  `json:"event"`
+	EndpointID string  `json:"endpoint_id"`
+	Attempt    int     `json:"attempt"`
+	At         string  `json:"at"`
+	DurationMs float64 `json:"duration_ms"`
+	StatusCode int     `json:"status_code,omitempty"`
+	Error      string  `json:"error,omitempty"`
+	Success    bool    `json:"success"`
+}
+
+type DeliveriesResponse struct {
+	Total      int64               `json:"total"`
+	Limit      int64               `json:"limit"`
+	Offset     int64               `json:"offset"`
+	Deliveries []*DeliveryResponse `json:"deliveries"`
+}
+
+type IncidentResponse struct {
 	ID           string `json:"id"`
-	Status       string `json:"status"`
-	LastChecked  string `json:"last_checked_at"`
-	ResponseTime string `json:"response_time"`
+	EndpointID   string `json:"endpoint_id"`
+	EndpointName string `json:"endpoint_name"`
+	// open, acknowledged or resolved
+	State    string `json:"state"`
+	Cause    string `json:"cause"`
+	OpenedAt string `json:"opened_at"`
+	// Missing if incident was not acknowledged
+	AcknowledgedAt string `json:"acknowledged_at,omitempty"`
+	AcknowledgedBy string `json:"acknowledged_by,omitempty"`
+	// Missing if incident is not resolved, `resolved_by` is missing if incident was resolved automatically
+	ResolvedAt string `json:"resolved_at,omitempty"`
+	ResolvedBy string `json:"resolved_by,omitempty"`
+	// Until now if incident is not resolved
+	DurationSec float64 `json:"duration_sec"`
+	// Only in single incident response, missing if incident is not escalated
+	Escalation *EscalationResponse `json:"escalation,omitempty"`
+	// Only in single incident response
+	Timeline []*TimelineEntryResponse `json:"timeline,omitempty"`
+}
+
+// Pending escalation of open incident
+type EscalationResponse struct {
+	PolicyID string `json:"policy_id"`
+	// Next step, starts from 1
+	NextStep int    `json:"next_step"`
+	NextAt   string `json:"next_at"`
+	// Rounds of policy steps done
+	Rounds int `json:"rounds"`
+}
+
+type TimelineEntryResponse struct {
+	At string `json:"at"`
+	// state, check, notification, comment or escalation
+	Type    string `json:"type"`
+	State   string `json:"state,omitempty"`
+	Message string `json:"message,omitempty"`
+	Author  string `json:"author,omitempty"`
+}
+
+type IncidentsResponse struct {
+	Total     int64               `json:"total"`
+	Limit     int64               `json:"limit"`
+	Offset    int64               `json:"offset"`
+	Incidents []*IncidentResponse `json:"incidents"`
 }
@@ -7,5 +7,33 @@ type Handler interface {
 	PostEndpoint(w http.ResponseWriter, r *http.Request)
 	PatchEndpoint(w http.ResponseWriter, r *http.Request)
 	DeleteEndpoint(w http.ResponseWriter, r *http.Request)
+	GetEndpointHistory(w http.ResponseWriter, r *http.Request)
+	GetEndpointUptime(w http.ResponseWriter, r *http.Request)
+	GetEndpointLatency(w http.ResponseWriter, r *http.Request)
+	GetProjectUptime(w http.ResponseWriter, r *http.Request)
+	PostHeartbeat(w http.ResponseWriter, r *http.Request)
 	MonitorSSE(w http.ResponseWriter, r *http.Request)
+	GetMetrics(w http.ResponseWriter, r *http.Request)
+	GetChannels(w http.ResponseWriter, r *http.Request)
+	PostChannel(w http.ResponseWriter, r *http.Request)
+	PatchChannel(w http.ResponseWriter, r *http.Request)
+	DeleteChannel(w http.ResponseWriter, r *http.Request)
+	GetChannelDeliveries(w http.ResponseWriter, r *http.Request)
+	GetIncidents(w http.ResponseWriter, r *http.Request)
+	GetIncident(w http.ResponseWriter, r *http.Request)
+	PostIncidentAcknowledge(w http.ResponseWriter, r *http.Request)
+	PostIncidentResolve(w http.ResponseWriter, r *http.Request)
+	PostIncidentComment(w http.ResponseWriter, r *http.Request)
+	GetEscalationPolicies(w http.ResponseWriter, r *http.Request)
+	PostEscalationPolicy(w http.ResponseWriter, r *http.Request)
+	PatchEscalationPolicy(w http.ResponseWriter, r *http.Request)
+	DeleteEscalationPolicy(w http.ResponseWriter, r *http.Request)
+	GetSchedules(w http.ResponseWriter, r *http.Request)
+	PostSchedule(w http.ResponseWriter, r *http.Request)
+	PatchSchedule(w http.ResponseWriter, r *http.Request)
+	DeleteSchedule(w http.ResponseWriter, r *http.Request)
+	GetScheduleOnCall(w http.ResponseWriter, r *http.Request)
+	GetScheduleOverrides(w http.ResponseWriter, r *http.Request)
+	PostScheduleOverride(w http.ResponseWriter, r *http.Request)
+	DeleteScheduleOverride(w http.ResponseWriter, r *http.Request)
 }
@@ -4,65 +4,121 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/wrtgvr/websites-monitor/internal/domain"
 	errs "github.com/wrtgvr/websites-monitor/internal/errors"
 	"github.com/wrtgvr/websites-monitor/internal/monitor"
 	"github.com/wrtgvr/websites-monitor/internal/storage"
 )
 
+const (
+	// Interval between SSE heartbeat events
+	sseHeartbeatInterval = 5 * time.Second
+	// Max SSE subscriber buffer size client can request
+	maxSSEBufferSize = 1024
+	// Default time window of history requests
+	defHistoryWindow = 24 * time.Hour
+	// Default and max page size of history requests
+	defHistoryLimit = 100
+	maxHistoryLimit = 1000
+	// Default time window of uptime requests
+	defUptimeWindow = 24 * time.Hour
+	// Default time window of latency requests
+	defLatencyWindow = 24 * time.Hour
+	// Max amount of histogram buckets client can request
+	maxLatencyBuckets = 50
+	// Max size of failure message sent with heartbeat
+	maxHeartbeatMessageSize = 1024
+	// Default and max page size of delivery log requests
+	defDeliveriesLimit = 50
+	maxDeliveriesLimit = 1000
+	// Default time window of incidents requests
+	defIncidentsWindow = 30 * 24 * time.Hour
+	// Default and max page size of incidents requests
+	defIncidentsLimit = 50
+	maxIncidentsLimit = 1000
+	// Default and max page size of endpoints requests
+	defEndpointsLimit = 20
+	maxEndpointsLimit = 1000
+)
+
 type HTTPHandler struct {
-	storage         storage.EndpointsStorage
+	storage         storage.Storage
+	hub             *monitor.Hub
+	scheduler       *monitor.Scheduler
 	responseTimeout time.Duration
+	// max window of uptime and latency requests, older checks are not kept
+	historyRetention time.Duration
 }
 
-func NewHTTPHandler(storage storage.EndpointsStorage, responseTimeount time.Duration) *HTTPHandler {
+func NewHTTPHandler(storage storage.Storage, hub *monitor.Hub, scheduler *monitor.Scheduler, responseTimeount, historyRetention time.Duration) *HTTPHandler {
 	return &HTTPHandler{
-		storage:         storage,
-		responseTimeout: responseTimeount,
+		storage:          storage,
+		hub:              hub,
+		scheduler:        scheduler,
+		responseTimeout:  responseTimeount,
+		historyRetention: historyRetention,
 	}
 }
 
 // GET /api/endpoints
 func (h *HTTPHandler) GetEndpoints(w http.ResponseWriter, r *http.Request) {
 	//* query params
-	var limit, offset int64
-	var defLimit int64 = 20
-	var defOffset int64 = 0
-	// limit
-	limitStr := r.URL.Query().Get("limit")
-	if limitStr == "" {
-		limit = defLimit
-	}
-	if v, err := strconv.ParseInt(limitStr, 10, 64); err != nil {
-		limit = defLimit
-	} else {
-		limit = v
-	}
-	// offset
-	offsetStr := r.URL.Query().Get("offset")
-	if offsetStr == "" {
-		offset = defOffset
+	limit, offset, err := h.parseLimitOffset(r, defEndpointsLimit, maxEndpointsLimit)
+	if err != nil {
+		h.error(w, http.StatusBadRequest, err.Error())
+		return
 	}
-	if v, err := strconv.ParseInt(offsetStr, 10, 64); err != nil {
-		offset = defOffset
-	} else {
-		offset = v
+	// cert_expires_within, only endpoints with certificate expiring within this duration
+	var certExpiresWithin time.Duration
+	if v := r.URL.Query().Get("cert_expires_within"); v != "" {
+		d, err := h.parseDuration(v)
+		if err != nil || d <= 0 {
+			h.error(w, http.StatusBadRequest, "cert_expires_within must be positive duration (e.g. 14d)")
+			return
+		}
+		certExpiresWithin = d
 	}
 
 	//* storage request
 	ctx, cancel := context.WithTimeout(context.Background(), h.responseTimeout)
 	defer cancel()
 
-	domainEps, err := h.storage.GetEndpoints(ctx, limit, offset)
-	if err != nil {
+	projectId, ok := h.projectID(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	// filtered list is paged after filtering, other lists are paged by storage
+	var domainEps []*domain.Endpoint
+	var appErr *errs.AppError
+	if certExpiresWithin > 0 {
+		domainEps, appErr = h.storage.GetEndpoints(ctx, projectId, "")
+	} else {
+		domainEps, appErr = h.storage.GetEndpointsPage(ctx, projectId, limit, offset)
+	}
+	if appErr != nil {
 		h.internalError(w)
 		return
 	}
+	if certExpiresWithin > 0 {
+		now := time.Now()
+		filtered := make([]*domain.Endpoint, 0, limit)
+		for _, ep := range domainEps {
+			if ep.Cert != nil && ep.Cert.ExpiresWithin(now, certExpiresWithin) {
+				filtered = append(filtered, ep)
+			}
+		}
+		n := int64(len(filtered))
+		start := min(offset, n)
+		domainEps = filtered[start:min(start+limit, n)]
+	}
 
 	//* http response
 	endpoints := make([]*EndpointResponse, len(domainEps))
@@ -81,14 +137,32 @@ func (h *HTTPHandler) PostEndpoint(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	//* check request
+	ep := &domain.EndpointInfo{
+		ID: uuid.NewString(),
+	}
+	if err := h.endpointConfigToDomain(&req.EndpointConfig, ep); err != nil {
+		h.error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if ep.CheckType() == domain.CheckTypeHeartbeat {
+		ep.HeartbeatToken = uuid.NewString()
+	}
+
 	//* storage request
 	ctx, cancel := context.WithTimeout(context.Background(), h.responseTimeout)
 	defer cancel()
 
-	if err := h.storage.AddEndpoint(ctx, &domain.EndpointInfo{
-		Name: req.Name,
-		URL:  req.URL,
-	}); err != nil {
+	projectId, ok := h.projectID(ctx, w, r)
+	if !ok {
+		return
+	}
+	ep.ProjectId = projectId
+	if !h.checkEscalationPolicyExists(ctx, w, projectId, ep.EscalationPolicyID) {
+		return
+	}
+
+	if err := h.storage.CreateEndpoint(ctx, ep); err != nil {
 		if err.Type == errs.TypeInternal {
 			h.internalError(w)
 			return
@@ -98,7 +172,7 @@ func (h *HTTPHandler) PostEndpoint(w http.ResponseWriter, r *http.Request) {
 	}
 
 	//* http response
-	w.WriteHeader(http.StatusCreated)
+	h.encodeJSONResponse(w, h.domainEndpointInfoToDTO(ep), http.StatusCreated)
 }
 
 // PATCH /api/endpoints/{id}
@@ -107,21 +181,72 @@ func (h *HTTPHandler) PatchEndpoint(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	if strings.TrimSpace(id) == "" {
 		h.error(w, http.StatusBadRequest, "id is required")
+		return
 	}
 
-	//* decode request
-	var req UpdateEndpointInfoRequest
+	//* storage request
+	ctx, cancel := context.WithTimeout(context.Background(), h.responseTimeout)
+	defer cancel()
+
+	projectId, ok := h.projectID(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	eps, appErr := h.storage.GetEndpoints(ctx, projectId, id)
+	if appErr != nil {
+		h.error(w, appErr.Code, appErr.Msg)
+		return
+	}
+	if len(eps) == 0 {
+		h.error(w, http.StatusNotFound, "endpoint not found")
+		return
+	}
+
+	//* decode request over current endpoint config
+	req := UpdateEndpointInfoRequest{
+		EndpointConfig: h.domainEndpointConfigToDTO(&eps[0].EndpointInfo),
+	}
 	if err := h.decodeJSONRequestBody(w, r, &req); err != nil {
 		return
 	}
 
 	//* check request
-	if id == "" {
-		h.error(w, http.StatusBadRequest, "id is required")
+	ep := &domain.EndpointInfo{
+		ID:             id,
+		ProjectId:      projectId,
+		HeartbeatToken: eps[0].HeartbeatToken,
+	}
+	if err := h.endpointConfigToDomain(&req.EndpointConfig, ep); err != nil {
+		h.error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	// endpoint became heartbeat check
+	if ep.CheckType() == domain.CheckTypeHeartbeat && ep.HeartbeatToken == "" {
+		ep.HeartbeatToken = uuid.NewString()
+	}
+
+	if !h.checkEscalationPolicyExists(ctx, w, projectId, ep.EscalationPolicyID) {
+		return
+	}
+
+	//* storage request
+	if appErr := h.storage.UpdateEndpointInfo(ctx, ep); appErr != nil {
+		h.error(w, appErr.Code, appErr.Msg)
 		return
 	}
-	if req.Name == "" && req.URL == "" {
-		h.error(w, http.StatusBadRequest, "required either new name or new url")
+
+	//* http response
+	h.encodeJSONResponse(w, h.domainEndpointInfoToDTO(ep), http.StatusOK)
+}
+
+// DELETE /api/endpoints/{id}
+// Active incident of endpoint is resolved and its escalation is stopped
+func (h *HTTPHandler) DeleteEndpoint(w http.ResponseWriter, r *http.Request) {
+	//* get id from path
+	id := r.PathValue("id")
+	if strings.TrimSpace(id) == "" {
+		h.error(w, http.StatusBadRequest, "id is required")
 		return
 	}
 
@@ -129,35 +254,257 @@ func (h *HTTPHandler) PatchEndpoint(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(context.Background(), h.responseTimeout)
 	defer cancel()
 
-	err := h.storage.ChangeEndpointInfo(ctx, &domain.EndpointInfo{
-		ID:   id,
-		Name: req.Name,
-		URL:  req.URL,
-	})
+	projectId, ok := h.projectID(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	err := h.storage.DeleteEndpoint(ctx, projectId, id)
 	if err != nil {
 		h.error(w, err.Code, err.Msg)
+		return
 	}
+
+	//* response
+	w.WriteHeader(http.StatusNoContent)
 }
 
-// DELETE /api/endpoints/{id}
-func (h *HTTPHandler) DeleteEndpoint(w http.ResponseWriter, r *http.Request) {
+// GET /api/endpoints/{id}/history
+func (h *HTTPHandler) GetEndpointHistory(w http.ResponseWriter, r *http.Request) {
+	//* get id from path
+	id := r.PathValue("id")
+	if strings.TrimSpace(id) == "" {
+		h.error(w, http.StatusBadRequest, "id is required")
+		return
+	}
+
+	//* query params
+	from, to, err := h.parseTimeRange(r, defHistoryWindow)
+	if err != nil {
+		h.error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	limit, offset, err := h.parseLimitOffset(r, defHistoryLimit, maxHistoryLimit)
+	if err != nil {
+		h.error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	//* storage request
+	ctx, cancel := context.WithTimeout(context.Background(), h.responseTimeout)
+	defer cancel()
+
+	projectId, ok := h.projectID(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	checks, total, appErr := h.storage.GetEndpointHistory(ctx, projectId, id, from, to, limit, offset)
+	if appErr != nil {
+		h.error(w, appErr.Code, appErr.Msg)
+		return
+	}
+
+	//* http response
+	resp := &EndpointHistoryResponse{
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+		Checks: make([]*EndpointCheckResponse, len(checks)),
+	}
+	for i, check := range checks {
+		resp.Checks[i] = h.domainEndpointCheckToDTO(check)
+	}
+
+	h.encodeJSONResponse(w, resp, http.StatusOK)
+}
+
+// GET /api/endpoints/{id}/uptime
+func (h *HTTPHandler) GetEndpointUptime(w http.ResponseWriter, r *http.Request) {
 	//* get id from path
 	id := r.PathValue("id")
 	if strings.TrimSpace(id) == "" {
 		h.error(w, http.StatusBadRequest, "id is required")
+		return
+	}
+
+	//* query params
+	from, to, err := h.parseWindow(r, defUptimeWindow)
+	if err != nil {
+		h.error(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
 	//* storage request
 	ctx, cancel := context.WithTimeout(context.Background(), h.responseTimeout)
 	defer cancel()
 
-	err := h.storage.DeleteEndpoint(ctx, id)
+	projectId, ok := h.projectID(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	eps, appErr := h.storage.GetEndpoints(ctx, projectId, id)
+	if appErr != nil {
+		h.error(w, appErr.Code, appErr.Msg)
+		return
+	}
+	if len(eps) == 0 {
+		h.error(w, http.StatusNotFound, "endpoint not found")
+		return
+	}
+
+	uptime, appErr := h.endpointUptime(ctx, &eps[0].EndpointInfo, from, to)
+	if appErr != nil {
+		h.error(w, appErr.Code, appErr.Msg)
+		return
+	}
+
+	//* http response
+	h.encodeJSONResponse(w, h.domainUptimeToDTO(uptime), http.StatusOK)
+}
+
+// GET /api/uptime
+func (h *HTTPHandler) GetProjectUptime(w http.ResponseWriter, r *http.Request) {
+	//* query params
+	from, to, err := h.parseWindow(r, defUptimeWindow)
 	if err != nil {
-		h.error(w, err.Code, err.Msg)
+		h.error(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	//* response
+	//* storage request
+	ctx, cancel := context.WithTimeout(context.Background(), h.responseTimeout)
+	defer cancel()
+
+	projectId, ok := h.projectID(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	eps, appErr := h.storage.GetEndpoints(ctx, projectId, "")
+	if appErr != nil {
+		h.error(w, appErr.Code, appErr.Msg)
+		return
+	}
+
+	//* compute uptime of every endpoint
+	resp := &ProjectUptimeResponse{
+		Endpoints: make([]*EndpointUptimeResponse, 0, len(eps)),
+	}
+	uptimes := make([]*domain.Uptime, 0, len(eps))
+	for _, ep := range eps {
+		uptime, appErr := h.endpointUptime(ctx, &ep.EndpointInfo, from, to)
+		if appErr != nil {
+			h.error(w, appErr.Code, appErr.Msg)
+			return
+		}
+		uptimes = append(uptimes, uptime)
+		resp.Endpoints = append(resp.Endpoints, &EndpointUptimeResponse{
+			ID:     ep.ID,
+			Name:   ep.Name,
+			Uptime: h.domainUptimeToDTO(uptime),
+		})
+	}
+	resp.Uptime = h.domainUptimeToDTO(domain.MergeUptimes(from, to, uptimes))
+
+	//* http response
+	h.encodeJSONResponse(w, resp, http.StatusOK)
+}
+
+// GET /api/endpoints/{id}/latency
+func (h *HTTPHandler) GetEndpointLatency(w http.ResponseWriter, r *http.Request) {
+	//* get id from path
+	id := r.PathValue("id")
+	if strings.TrimSpace(id) == "" {
+		h.error(w, http.StatusBadRequest, "id is required")
+		return
+	}
+
+	//* query params
+	from, to, err := h.parseWindow(r, defLatencyWindow)
+	if err != nil {
+		h.error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	buckets, err := h.parseLatencyBuckets(r)
+	if err != nil {
+		h.error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	//* storage request
+	ctx, cancel := context.WithTimeout(context.Background(), h.responseTimeout)
+	defer cancel()
+
+	projectId, ok := h.projectID(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	// history is streamed, only latencies are kept
+	lc := domain.NewLatencyCollector(from, to, buckets)
+	if appErr := h.storage.ScanEndpointHistory(ctx, projectId, id, from, to, lc.Add); appErr != nil {
+		h.error(w, appErr.Code, appErr.Msg)
+		return
+	}
+
+	//* http response
+	h.encodeJSONResponse(w, h.domainLatencyStatsToDTO(lc.Stats()), http.StatusOK)
+}
+
+// Compute endpoint uptime from its check history within window, history is streamed
+func (h *HTTPHandler) endpointUptime(ctx context.Context, ep *domain.EndpointInfo, from, to time.Time) (*domain.Uptime, *errs.AppError) {
+	uptime := domain.NewUptime(from, to, ep.SLOTarget)
+	if appErr := h.storage.ScanEndpointHistory(ctx, ep.ProjectId, ep.ID, from, to, uptime.Add); appErr != nil {
+		return nil, appErr
+	}
+	return uptime, nil
+}
+
+// POST /api/heartbeat/{token}
+// POST /api/heartbeat/{token}/{signal}
+func (h *HTTPHandler) PostHeartbeat(w http.ResponseWriter, r *http.Request) {
+	//* get token and signal from path
+	token := r.PathValue("token")
+	if strings.TrimSpace(token) == "" {
+		h.error(w, http.StatusBadRequest, "token is required")
+		return
+	}
+	signal := domain.HeartbeatSuccess
+	if v := r.PathValue("signal"); v != "" {
+		signal = domain.HeartbeatSignal(v)
+	}
+	if !signal.Valid() {
+		h.error(w, http.StatusBadRequest, "signal must be one of: start, success, fail")
+		return
+	}
+
+	// failure reason, optional
+	var message string
+	if signal == domain.HeartbeatFail {
+		b, err := io.ReadAll(io.LimitReader(r.Body, maxHeartbeatMessageSize))
+		if err != nil {
+			h.error(w, http.StatusBadRequest, "failed to read request body")
+			return
+		}
+		message = strings.TrimSpace(string(b))
+	}
+
+	//* record signal
+	ctx, cancel := context.WithTimeout(context.Background(), h.responseTimeout)
+	defer cancel()
+
+	if appErr := h.scheduler.ReceiveHeartbeat(ctx, token, signal, message); appErr != nil {
+		if appErr.Type == errs.TypeInternal {
+			h.internalError(w)
+			return
+		}
+		h.error(w, appErr.Code, appErr.Msg)
+		return
+	}
+
+	//* http response
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -168,40 +515,975 @@ func (h *HTTPHandler) MonitorSSE(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Connection", "keep-alive")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 
-	mntr := monitor.NewMonitor(h.storage)
-	go mntr.Run()
+	//* get project
+	ctx, cancel := context.WithTimeout(r.Context(), h.responseTimeout)
+	projectId, ok := h.projectID(ctx, w, r)
+	cancel()
+	if !ok {
+		return
+	}
+
+	//* query params
+	opts := monitor.SubscribeOptions{
+		ProjectID: projectId,
+	}
+	// endpoint_ids
+	if idsStr := r.URL.Query().Get("endpoint_ids"); idsStr != "" {
+		for _, id := range strings.Split(idsStr, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				opts.EndpointIDs = append(opts.EndpointIDs, id)
+			}
+		}
+	}
+	// buffer
+	if bufStr := r.URL.Query().Get("buffer"); bufStr != "" {
+		v, err := strconv.Atoi(bufStr)
+		if err != nil || v <= 0 || v > maxSSEBufferSize {
+			h.error(w, http.StatusBadRequest, fmt.Sprintf("buffer must be in range 1-%d", maxSSEBufferSize))
+			return
+		}
+		opts.BufferSize = v
+	}
+	// drop_policy
+	if policyStr := r.URL.Query().Get("drop_policy"); policyStr != "" {
+		opts.DropPolicy = monitor.DropPolicy(policyStr)
+		if !opts.DropPolicy.Valid() {
+			h.error(w, http.StatusBadRequest, "drop_policy must be one of: newest, oldest, disconnect")
+			return
+		}
+	}
+
+	// Last-Event-ID
+	lastIdStr := r.Header.Get("Last-Event-ID")
+	if lastIdStr == "" {
+		lastIdStr = r.URL.Query().Get("last_event_id")
+	}
+	if lastIdStr != "" {
+		v, err := strconv.ParseUint(lastIdStr, 10, 64)
+		if err != nil {
+			h.error(w, http.StatusBadRequest, "invalid last event id")
+			return
+		}
+		opts.LastEventID = v
+	}
+
+	sub := h.hub.Subscribe(opts)
+	defer sub.Close()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
 
 	rc := http.NewResponseController(w)
 
 	for {
 		select {
 		case <-r.Context().Done():
-			mntr.Stop()
 			return
-		case epStatus := <-mntr.Out:
+		case e, ok := <-sub.C:
+			if !ok {
+				return
+			}
 			// endpoint status recieved
-			b, err := json.Marshal(epStatus)
+			b, err := json.Marshal(h.domainEndpointStatusToDTO(e.Status))
 			if err != nil {
-				mntr.Stop()
 				return
 			}
-			_, err = fmt.Fprintf(w, "event: %s\n\ndata: %s\n\n", "pingresult", b)
+			_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, "pingresult", b)
 			if err != nil {
-				mntr.Stop()
 				return
 			}
 
-			err = rc.Flush()
-			if err != nil {
-				mntr.Stop()
+			if err = rc.Flush(); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", "heartbeat", "Heartbeat")
+			if err := rc.Flush(); err != nil {
 				return
 			}
-		default:
-			// Heartbeat
-			fmt.Fprintf(w, "event: %s\n\ndata: %s\n\n", "heartbeat", "Heartbeat")
-			rc.Flush()
-
-			time.Sleep(5 * time.Second)
 		}
 	}
 }
+
+// GET /metrics
+// Scheduler metrics in prometheus text format
+func (h *HTTPHandler) GetMetrics(w http.ResponseWriter, r *http.Request) {
+	stats := h.scheduler.Stats()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	h.writeMetric(w, "monitor_endpoints_scheduled", "gauge", "Endpoints scheduled for checks.", stats.Endpoints)
+	h.writeMetric(w, "monitor_checks_running", "gauge", "Checks in progress.", stats.Running)
+	h.writeMetric(w, "monitor_checks_total", "counter", "Checks finished since start.", stats.Checks)
+	h.writeMetric(w, "monitor_checks_skipped_total", "counter", "Checks skipped because previous check of endpoint was still running.", stats.Skipped)
+}
+
+// GET /api/channels
+func (h *HTTPHandler) GetChannels(w http.ResponseWriter, r *http.Request) {
+	//* storage request
+	ctx, cancel := context.WithTimeout(context.Background(), h.responseTimeout)
+	defer cancel()
+
+	projectId, ok := h.projectID(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	channels, appErr := h.storage.GetNotificationChannels(ctx, projectId)
+	if appErr != nil {
+		h.internalError(w)
+		return
+	}
+
+	//* http response
+	resp := make([]*ChannelResponse, len(channels))
+	for i, ch := range channels {
+		resp[i] = h.domainChannelToDTO(ch)
+	}
+
+	h.encodeJSONResponse(w, resp, http.StatusOK)
+}
+
+// POST /api/channels
+func (h *HTTPHandler) PostChannel(w http.ResponseWriter, r *http.Request) {
+	//* decode request
+	req := CreateChannelRequest{
+		ChannelConfig: ChannelConfig{
+			Enabled: true,
+		},
+	}
+	if err := h.decodeJSONRequestBody(w, r, &req); err != nil {
+		return
+	}
+	//* check request
+	ch := &domain.NotificationChannel{
+		ID: uuid.NewString(),
+	}
+	if err := h.channelConfigToDomain(&req.ChannelConfig, ch); err != nil {
+		h.error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	//* storage request
+	ctx, cancel := context.WithTimeout(context.Background(), h.responseTimeout)
+	defer cancel()
+
+	projectId, ok := h.projectID(ctx, w, r)
+	if !ok {
+		return
+	}
+	ch.ProjectId = projectId
+
+	if err := h.storage.CreateNotificationChannel(ctx, ch); err != nil {
+		if err.Type == errs.TypeInternal {
+			h.internalError(w)
+			return
+		}
+		h.error(w, err.Code, err.Msg)
+		return
+	}
+
+	//* http response
+	h.encodeJSONResponse(w, h.savedChannelToDTO(&req.ChannelConfig, ch), http.StatusCreated)
+}
+
+// PATCH /api/channels/{id}
+func (h *HTTPHandler) PatchChannel(w http.ResponseWriter, r *http.Request) {
+	//* get id from path
+	id := r.PathValue("id")
+	if strings.TrimSpace(id) == "" {
+		h.error(w, http.StatusBadRequest, "id is required")
+		return
+	}
+
+	//* storage request
+	ctx, cancel := context.WithTimeout(context.Background(), h.responseTimeout)
+	defer cancel()
+
+	projectId, ok := h.projectID(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	current, appErr := h.storage.GetNotificationChannel(ctx, projectId, id)
+	if appErr != nil {
+		h.error(w, appErr.Code, appErr.Msg)
+		return
+	}
+
+	//* decode request over current channel config
+	req := UpdateChannelRequest{
+		ChannelConfig: h.domainChannelToConfig(current),
+	}
+	if err := h.decodeJSONRequestBody(w, r, &req); err != nil {
+		return
+	}
+	//* check request
+	ch := &domain.NotificationChannel{
+		ID:        id,
+		ProjectId: projectId,
+	}
+	if err := h.channelConfigToDomain(&req.ChannelConfig, ch); err != nil {
+		h.error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	//* storage request
+	if appErr := h.storage.UpdateNotificationChannel(ctx, ch); appErr != nil {
+		h.error(w, appErr.Code, appErr.Msg)
+		return
+	}
+
+	//* http response
+	h.encodeJSONResponse(w, h.savedChannelToDTO(&req.ChannelConfig, ch), http.StatusOK)
+}
+
+// DELETE /api/channels/{id}
+func (h *HTTPHandler) DeleteChannel(w http.ResponseWriter, r *http.Request) {
+	//* get id from path
+	id := r.PathValue("id")
+	if strings.TrimSpace(id) == "" {
+		h.error(w, http.StatusBadRequest, "id is required")
+		return
+	}
+
+	//* storage request
+	ctx, cancel := context.WithTimeout(context.Background(), h.responseTimeout)
+	defer cancel()
+
+	projectId, ok := h.projectID(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	if appErr := h.storage.DeleteNotificationChannel(ctx, projectId, id); appErr != nil {
+		h.error(w, appErr.Code, appErr.Msg)
+		return
+	}
+
+	//* response
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET /api/channels/{id}/deliveries
+// Delivery log of channel, newest attempts first
+func (h *HTTPHandler) GetChannelDeliveries(w http.ResponseWriter, r *http.Request) {
+	//* get id from path
+	id := r.PathValue("id")
+	if strings.TrimSpace(id) == "" {
+		h.error(w, http.StatusBadRequest, "id is required")
+		return
+	}
+
+	//* query params
+	limit, offset, err := h.parseLimitOffset(r, defDeliveriesLimit, maxDeliveriesLimit)
+	if err != nil {
+		h.error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	//* storage request
+	ctx, cancel := context.WithTimeout(context.Background(), h.responseTimeout)
+	defer cancel()
+
+	projectId, ok := h.projectID(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	deliveries, total, appErr := h.storage.GetNotificationDeliveries(ctx, projectId, id, limit, offset)
+	if appErr != nil {
+		h.error(w, appErr.Code, appErr.Msg)
+		return
+	}
+
+	//* http response
+	resp := &DeliveriesResponse{
+		Total:      total,
+		Limit:      limit,
+		Offset:     offset,
+		Deliveries: make([]*DeliveryResponse, len(deliveries)),
+	}
+	for i, d := range deliveries {
+		resp.Deliveries[i] = h.domainDeliveryToDTO(d)
+	}
+
+	h.encodeJSONResponse(w, resp, http.StatusOK)
+}
+
+// GET /api/incidents
+func (h *HTTPHandler) GetIncidents(w http.ResponseWriter, r *http.Request) {
+	//* query params
+	from, to, err := h.parseTimeRange(r, defIncidentsWindow)
+	if err != nil {
+		h.error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	limit, offset, err := h.parseLimitOffset(r, defIncidentsLimit, maxIncidentsLimit)
+	if err != nil {
+		h.error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	filter, err := h.parseIncidentFilter(r)
+	if err != nil {
+		h.error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	//* storage request
+	ctx, cancel := context.WithTimeout(context.Background(), h.responseTimeout)
+	defer cancel()
+
+	projectId, ok := h.projectID(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	incidents, total, appErr := h.storage.GetIncidents(ctx, projectId, filter, from, to, limit, offset)
+	if appErr != nil {
+		h.internalError(w)
+		return
+	}
+
+	//* http response
+	resp := &IncidentsResponse{
+		Total:     total,
+		Limit:     limit,
+		Offset:    offset,
+		Incidents: make([]*IncidentResponse, len(incidents)),
+	}
+	for i, inc := range incidents {
+		resp.Incidents[i] = h.domainIncidentToDTO(inc, nil)
+	}
+
+	h.encodeJSONResponse(w, resp, http.StatusOK)
+}
+
+// GET /api/incidents/{id}
+// Incident with its timeline
+func (h *HTTPHandler) GetIncident(w http.ResponseWriter, r *http.Request) {
+	//* get id from path
+	id := r.PathValue("id")
+	if strings.TrimSpace(id) == "" {
+		h.error(w, http.StatusBadRequest, "id is required")
+		return
+	}
+
+	//* storage request
+	ctx, cancel := context.WithTimeout(context.Background(), h.responseTimeout)
+	defer cancel()
+
+	projectId, ok := h.projectID(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	inc, appErr := h.storage.GetIncident(ctx, projectId, id)
+	if appErr != nil {
+		h.error(w, appErr.Code, appErr.Msg)
+		return
+	}
+	timeline, appErr := h.storage.GetIncidentTimeline(ctx, projectId, id)
+	if appErr != nil {
+		h.internalError(w)
+		return
+	}
+	escalation, appErr := h.storage.GetEscalation(ctx, projectId, id)
+	if appErr != nil && appErr.Type != errs.TypeNotFound {
+		h.internalError(w)
+		return
+	}
+
+	//* http response
+	resp := h.domainIncidentToDTO(inc, timeline)
+	if escalation != nil {
+		resp.Escalation = h.domainEscalationToDTO(escalation)
+	}
+	h.encodeJSONResponse(w, resp, http.StatusOK)
+}
+
+// POST /api/incidents/{id}/acknowledge
+func (h *HTTPHandler) PostIncidentAcknowledge(w http.ResponseWriter, r *http.Request) {
+	h.updateIncidentState(w, r, domain.IncidentAcknowledged, func(inc *domain.Incident, at time.Time, by string) error {
+		return inc.Acknowledge(at, by)
+	})
+}
+
+// POST /api/incidents/{id}/resolve
+func (h *HTTPHandler) PostIncidentResolve(w http.ResponseWriter, r *http.Request) {
+	h.updateIncidentState(w, r, domain.IncidentResolved, func(inc *domain.Incident, at time.Time, by string) error {
+		// resolved manually, so name is required to tell it from automatic resolve
+		if by == "" {
+			by = "api"
+		}
+		return inc.Resolve(at, by)
+	})
+}
+
+// Apply manual incident state change and add it to timeline.
+// Response with conflict if change is not allowed in current incident state
+func (h *HTTPHandler) updateIncidentState(w http.ResponseWriter, r *http.Request, state domain.IncidentState, update func(inc *domain.Incident, at time.Time, by string) error) {
+	//* get id from path
+	id := r.PathValue("id")
+	if strings.TrimSpace(id) == "" {
+		h.error(w, http.StatusBadRequest, "id is required")
+		return
+	}
+
+	//* decode request, body is optional
+	var req IncidentActionRequest
+	if r.ContentLength != 0 {
+		if err := h.decodeJSONRequestBody(w, r, &req); err != nil {
+			return
+		}
+	}
+	by := strings.TrimSpace(req.By)
+
+	//* storage request
+	ctx, cancel := context.WithTimeout(context.Background(), h.responseTimeout)
+	defer cancel()
+
+	projectId, ok := h.projectID(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	inc, appErr := h.storage.UpdateIncident(ctx, projectId, id, func(inc *domain.Incident) error {
+		return update(inc, now, by)
+	})
+	if appErr != nil {
+		if appErr.Type == errs.TypeInternal {
+			h.internalError(w)
+			return
+		}
+		h.error(w, appErr.Code, appErr.Msg)
+		return
+	}
+
+	entry := &domain.TimelineEntry{
+		At:      now,
+		Type:    domain.TimelineState,
+		State:   string(state),
+		Message: "incident " + string(state),
+		Author:  by,
+	}
+	if appErr := h.storage.AddIncidentTimelineEntry(ctx, projectId, id, entry); appErr != nil {
+		h.internalError(w)
+		return
+	}
+
+	//* http response
+	h.encodeJSONResponse(w, h.domainIncidentToDTO(inc, nil), http.StatusOK)
+}
+
+// POST /api/incidents/{id}/comments
+func (h *HTTPHandler) PostIncidentComment(w http.ResponseWriter, r *http.Request) {
+	//* get id from path
+	id := r.PathValue("id")
+	if strings.TrimSpace(id) == "" {
+		h.error(w, http.StatusBadRequest, "id is required")
+		return
+	}
+
+	//* decode request
+	var req CommentRequest
+	if err := h.decodeJSONRequestBody(w, r, &req); err != nil {
+		return
+	}
+
+	//* check request
+	text := strings.TrimSpace(req.Text)
+	if text == "" {
+		h.error(w, http.StatusBadRequest, "text is required")
+		return
+	}
+	if len(text) > domain.MaxCommentLength {
+		h.error(w, http.StatusBadRequest, fmt.Sprintf("text cannot be longer than %d bytes", domain.MaxCommentLength))
+		return
+	}
+	entry := &domain.TimelineEntry{
+		At:      time.Now(),
+		Type:    domain.TimelineComment,
+		Message: text,
+		Author:  strings.TrimSpace(req.Author),
+	}
+
+	//* storage request
+	ctx, cancel := context.WithTimeout(context.Background(), h.responseTimeout)
+	defer cancel()
+
+	projectId, ok := h.projectID(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	if appErr := h.storage.AddIncidentTimelineEntry(ctx, projectId, id, entry); appErr != nil {
+		if appErr.Type == errs.TypeInternal {
+			h.internalError(w)
+			return
+		}
+		h.error(w, appErr.Code, appErr.Msg)
+		return
+	}
+
+	//* http response
+	h.encodeJSONResponse(w, h.domainTimelineEntryToDTO(entry), http.StatusCreated)
+}
+
+// GET /api/escalation-policies
+func (h *HTTPHandler) GetEscalationPolicies(w http.ResponseWriter, r *http.Request) {
+	//* storage request
+	ctx, cancel := context.WithTimeout(context.Background(), h.responseTimeout)
+	defer cancel()
+
+	projectId, ok := h.projectID(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	policies, appErr := h.storage.GetEscalationPolicies(ctx, projectId)
+	if appErr != nil {
+		h.internalError(w)
+		return
+	}
+
+	//* http response
+	resp := make([]*EscalationPolicyResponse, len(policies))
+	for i, p := range policies {
+		resp[i] = h.domainEscalationPolicyToDTO(p)
+	}
+
+	h.encodeJSONResponse(w, resp, http.StatusOK)
+}
+
+// POST /api/escalation-policies
+func (h *HTTPHandler) PostEscalationPolicy(w http.ResponseWriter, r *http.Request) {
+	//* decode request
+	var req CreateEscalationPolicyRequest
+	if err := h.decodeJSONRequestBody(w, r, &req); err != nil {
+		return
+	}
+
+	//* check request
+	p := &domain.EscalationPolicy{
+		ID: uuid.NewString(),
+	}
+	if err := h.escalationPolicyConfigToDomain(&req.EscalationPolicyConfig, p); err != nil {
+		h.error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	//* storage request
+	ctx, cancel := context.WithTimeout(context.Background(), h.responseTimeout)
+	defer cancel()
+
+	projectId, ok := h.projectID(ctx, w, r)
+	if !ok {
+		return
+	}
+	p.ProjectId = projectId
+	if !h.checkEscalationTargetsExist(ctx, w, p) {
+		return
+	}
+
+	if err := h.storage.CreateEscalationPolicy(ctx, p); err != nil {
+		if err.Type == errs.TypeInternal {
+			h.internalError(w)
+			return
+		}
+		h.error(w, err.Code, err.Msg)
+		return
+	}
+
+	//* http response
+	h.encodeJSONResponse(w, h.domainEscalationPolicyToDTO(p), http.StatusCreated)
+}
+
+// PATCH /api/escalation-policies/{id}
+// Running escalations continue with changed steps
+func (h *HTTPHandler) PatchEscalationPolicy(w http.ResponseWriter, r *http.Request) {
+	//* get id from path
+	id := r.PathValue("id")
+	if strings.TrimSpace(id) == "" {
+		h.error(w, http.StatusBadRequest, "id is required")
+		return
+	}
+
+	//* storage request
+	ctx, cancel := context.WithTimeout(context.Background(), h.responseTimeout)
+	defer cancel()
+
+	projectId, ok := h.projectID(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	current, appErr := h.storage.GetEscalationPolicy(ctx, projectId, id)
+	if appErr != nil {
+		h.error(w, appErr.Code, appErr.Msg)
+		return
+	}
+
+	//* decode request over current policy config
+	req := UpdateEscalationPolicyRequest{
+		EscalationPolicyConfig: h.domainEscalationPolicyToDTO(current).EscalationPolicyConfig,
+	}
+	if err := h.decodeJSONRequestBody(w, r, &req); err != nil {
+		return
+	}
+
+	//* check request
+	p := &domain.EscalationPolicy{
+		ID:        id,
+		ProjectId: projectId,
+	}
+	if err := h.escalationPolicyConfigToDomain(&req.EscalationPolicyConfig, p); err != nil {
+		h.error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !h.checkEscalationTargetsExist(ctx, w, p) {
+		return
+	}
+
+	//* storage request
+	if appErr := h.storage.UpdateEscalationPolicy(ctx, p); appErr != nil {
+		h.error(w, appErr.Code, appErr.Msg)
+		return
+	}
+
+	//* http response
+	h.encodeJSONResponse(w, h.domainEscalationPolicyToDTO(p), http.StatusOK)
+}
+
+// DELETE /api/escalation-policies/{id}
+// Endpoints that use policy are left without escalation, running escalations are stopped
+func (h *HTTPHandler) DeleteEscalationPolicy(w http.ResponseWriter, r *http.Request) {
+	//* get id from path
+	id := r.PathValue("id")
+	if strings.TrimSpace(id) == "" {
+		h.error(w, http.StatusBadRequest, "id is required")
+		return
+	}
+
+	//* storage request
+	ctx, cancel := context.WithTimeout(context.Background(), h.responseTimeout)
+	defer cancel()
+
+	projectId, ok := h.projectID(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	if appErr := h.storage.DeleteEscalationPolicy(ctx, projectId, id); appErr != nil {
+		h.error(w, appErr.Code, appErr.Msg)
+		return
+	}
+
+	//* response
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET /api/schedules
+func (h *HTTPHandler) GetSchedules(w http.ResponseWriter, r *http.Request) {
+	//* storage request
+	ctx, cancel := context.WithTimeout(context.Background(), h.responseTimeout)
+	defer cancel()
+
+	projectId, ok := h.projectID(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	schedules, appErr := h.storage.GetSchedules(ctx, projectId)
+	if appErr != nil {
+		h.internalError(w)
+		return
+	}
+
+	//* http response
+	resp := make([]*ScheduleResponse, len(schedules))
+	for i, s := range schedules {
+		resp[i] = h.domainScheduleToDTO(s)
+	}
+
+	h.encodeJSONResponse(w, resp, http.StatusOK)
+}
+
+// POST /api/schedules
+func (h *HTTPHandler) PostSchedule(w http.ResponseWriter, r *http.Request) {
+	//* decode request over defaults
+	req := CreateScheduleRequest{
+		ScheduleConfig: ScheduleConfig{
+			Rotation:    string(domain.RotationWeekly),
+			HandoffTime: "09:00",
+			HandoffDay:  "monday",
+			Start:       time.Now().Format(time.RFC3339),
+		},
+	}
+	if err := h.decodeJSONRequestBody(w, r, &req); err != nil {
+		return
+	}
+
+	//* check request
+	s := &domain.OnCallSchedule{
+		ID: uuid.NewString(),
+	}
+	if err := h.scheduleConfigToDomain(&req.ScheduleConfig, s); err != nil {
+		h.error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	//* storage request
+	ctx, cancel := context.WithTimeout(context.Background(), h.responseTimeout)
+	defer cancel()
+
+	projectId, ok := h.projectID(ctx, w, r)
+	if !ok {
+		return
+	}
+	s.ProjectId = projectId
+
+	if err := h.storage.CreateSchedule(ctx, s); err != nil {
+		if err.Type == errs.TypeInternal {
+			h.internalError(w)
+			return
+		}
+		h.error(w, err.Code, err.Msg)
+		return
+	}
+
+	//* http response
+	h.encodeJSONResponse(w, h.domainScheduleToDTO(s), http.StatusCreated)
+}
+
+// PATCH /api/schedules/{id}
+// Overrides are kept
+func (h *HTTPHandler) PatchSchedule(w http.ResponseWriter, r *http.Request) {
+	//* get id from path
+	id := r.PathValue("id")
+	if strings.TrimSpace(id) == "" {
+		h.error(w, http.StatusBadRequest, "id is required")
+		return
+	}
+
+	//* storage request
+	ctx, cancel := context.WithTimeout(context.Background(), h.responseTimeout)
+	defer cancel()
+
+	projectId, ok := h.projectID(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	current, appErr := h.storage.GetSchedule(ctx, projectId, id)
+	if appErr != nil {
+		h.error(w, appErr.Code, appErr.Msg)
+		return
+	}
+
+	//* decode request over current schedule config
+	req := UpdateScheduleRequest{
+		ScheduleConfig: h.domainScheduleToDTO(current).ScheduleConfig,
+	}
+	if err := h.decodeJSONRequestBody(w, r, &req); err != nil {
+		return
+	}
+
+	//* check request
+	s := &domain.OnCallSchedule{
+		ID:        id,
+		ProjectId: projectId,
+	}
+	if err := h.scheduleConfigToDomain(&req.ScheduleConfig, s); err != nil {
+		h.error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	//* storage request
+	if appErr := h.storage.UpdateSchedule(ctx, s); appErr != nil {
+		h.error(w, appErr.Code, appErr.Msg)
+		return
+	}
+
+	//* http response
+	h.encodeJSONResponse(w, h.domainScheduleToDTO(s), http.StatusOK)
+}
+
+// DELETE /api/schedules/{id}
+// Escalation steps that use schedule notify their channel recipients
+func (h *HTTPHandler) DeleteSchedule(w http.ResponseWriter, r *http.Request) {
+	//* get id from path
+	id := r.PathValue("id")
+	if strings.TrimSpace(id) == "" {
+		h.error(w, http.StatusBadRequest, "id is required")
+		return
+	}
+
+	//* storage request
+	ctx, cancel := context.WithTimeout(context.Background(), h.responseTimeout)
+	defer cancel()
+
+	projectId, ok := h.projectID(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	if appErr := h.storage.DeleteSchedule(ctx, projectId, id); appErr != nil {
+		h.error(w, appErr.Code, appErr.Msg)
+		return
+	}
+
+	//* response
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET /api/schedules/{id}/on-call?at=
+// `at` is RFC3339 time, now by default
+func (h *HTTPHandler) GetScheduleOnCall(w http.ResponseWriter, r *http.Request) {
+	//* get id from path
+	id := r.PathValue("id")
+	if strings.TrimSpace(id) == "" {
+		h.error(w, http.StatusBadRequest, "id is required")
+		return
+	}
+
+	//* parse query
+	at := time.Now()
+	if atStr := r.URL.Query().Get("at"); atStr != "" {
+		var err error
+		if at, err = time.Parse(time.RFC3339, atStr); err != nil {
+			h.error(w, http.StatusBadRequest, "at must be in RFC3339 format")
+			return
+		}
+	}
+
+	//* storage request
+	ctx, cancel := context.WithTimeout(context.Background(), h.responseTimeout)
+	defer cancel()
+
+	projectId, ok := h.projectID(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	s, appErr := h.storage.GetSchedule(ctx, projectId, id)
+	if appErr != nil {
+		h.error(w, appErr.Code, appErr.Msg)
+		return
+	}
+	overrides, appErr := h.storage.GetScheduleOverrides(ctx, projectId, id, at)
+	if appErr != nil {
+		h.error(w, appErr.Code, appErr.Msg)
+		return
+	}
+
+	//* http response
+	h.encodeJSONResponse(w, h.domainShiftToDTO(s, at, s.OnCallAt(at, overrides)), http.StatusOK)
+}
+
+// GET /api/schedules/{id}/overrides
+// Active and upcoming overrides, earliest end first
+func (h *HTTPHandler) GetScheduleOverrides(w http.ResponseWriter, r *http.Request) {
+	//* get id from path
+	id := r.PathValue("id")
+	if strings.TrimSpace(id) == "" {
+		h.error(w, http.StatusBadRequest, "id is required")
+		return
+	}
+
+	//* storage request
+	ctx, cancel := context.WithTimeout(context.Background(), h.responseTimeout)
+	defer cancel()
+
+	projectId, ok := h.projectID(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	overrides, appErr := h.storage.GetScheduleOverrides(ctx, projectId, id, time.Now())
+	if appErr != nil {
+		h.error(w, appErr.Code, appErr.Msg)
+		return
+	}
+
+	//* http response
+	resp := make([]*OverrideResponse, len(overrides))
+	for i, o := range overrides {
+		resp[i] = h.domainOverrideToDTO(o)
+	}
+
+	h.encodeJSONResponse(w, resp, http.StatusOK)
+}
+
+// POST /api/schedules/{id}/overrides
+func (h *HTTPHandler) PostScheduleOverride(w http.ResponseWriter, r *http.Request) {
+	//* get id from path
+	id := r.PathValue("id")
+	if strings.TrimSpace(id) == "" {
+		h.error(w, http.StatusBadRequest, "id is required")
+		return
+	}
+
+	//* decode request
+	var req OverrideRequest
+	if err := h.decodeJSONRequestBody(w, r, &req); err != nil {
+		return
+	}
+
+	//* check request
+	o, err := h.overrideRequestToDomain(&req)
+	if err != nil {
+		h.error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	o.ID = uuid.NewString()
+
+	//* storage request
+	ctx, cancel := context.WithTimeout(context.Background(), h.responseTimeout)
+	defer cancel()
+
+	projectId, ok := h.projectID(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	if appErr := h.storage.AddScheduleOverride(ctx, projectId, id, o); appErr != nil {
+		if appErr.Type == errs.TypeInternal {
+			h.internalError(w)
+			return
+		}
+		h.error(w, appErr.Code, appErr.Msg)
+		return
+	}
+
+	//* http response
+	h.encodeJSONResponse(w, h.domainOverrideToDTO(o), http.StatusCreated)
+}
+
+// DELETE /api/schedules/{id}/overrides/{override_id}
+func (h *HTTPHandler) DeleteScheduleOverride(w http.ResponseWriter, r *http.Request) {
+	//* get ids from path
+	id := r.PathValue("id")
+	overrideId := r.PathValue("override_id")
+	if strings.TrimSpace(id) == "" || strings.TrimSpace(overrideId) == "" {
+		h.error(w, http.StatusBadRequest, "id and override_id are required")
+		return
+	}
+
+	//* storage request
+	ctx, cancel := context.WithTimeout(context.Background(), h.responseTimeout)
+	defer cancel()
+
+	projectId, ok := h.projectID(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	if appErr := h.storage.DeleteScheduleOverride(ctx, projectId, id, overrideId); appErr != nil {
+		h.error(w, appErr.Code, appErr.Msg)
+		return
+	}
+
+	//* response
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,10 +1,26 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/wrtgvr/websites-monitor/internal/domain"
+	errs "github.com/wrtgvr/websites-monitor/internal/errors"
+)
+
+const (
+	// header with project admin api key
+	headerAPIKey = "X-API-Key"
+	// query param with project admin api key (for clients that can't set headers, e.g. EventSource)
+	queryAPIKey = "api_key"
 )
 
 // shortcut for `http.Error()`
@@ -31,38 +47,754 @@ func (h *HTTPHandler) decodeJSONRequestBody(w http.ResponseWriter, r *http.Reque
 // Response with InternalServerError on encode error
 // Response with `successCode` on successful encoding
 func (h *HTTPHandler) encodeJSONResponse(w http.ResponseWriter, v any, successCode int) error {
-	if err := json.NewEncoder(w).Encode(v); err != nil {
+	b, err := json.Marshal(v)
+	if err != nil {
 		http.Error(w, "internal server error", http.StatusInternalServerError)
 		return err
 	}
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(successCode)
-	return nil
+	_, err = w.Write(b)
+	return err
+}
+
+// Get project id by admin api key from `X-API-Key` header or `api_key` query param.
+// Response with error if key is missing or project not found
+func (h *HTTPHandler) projectID(ctx context.Context, w http.ResponseWriter, r *http.Request) (string, bool) {
+	key := strings.TrimSpace(r.Header.Get(headerAPIKey))
+	if key == "" {
+		key = strings.TrimSpace(r.URL.Query().Get(queryAPIKey))
+	}
+	if key == "" {
+		h.error(w, http.StatusUnauthorized, "api key is required")
+		return "", false
+	}
+
+	projectId, err := h.storage.GetProjectIDByAPIKey(ctx, key)
+	if err != nil {
+		if err.Type == errs.TypeInternal {
+			h.internalError(w)
+			return "", false
+		}
+		h.error(w, http.StatusUnauthorized, "invalid api key")
+		return "", false
+	}
+	return projectId, true
+}
+
+// Check that escalation policy referenced by endpoint exists. Empty id means no policy.
+// Writes error response if it doesn't
+func (h *HTTPHandler) checkEscalationPolicyExists(ctx context.Context, w http.ResponseWriter, projectId, policyId string) bool {
+	if policyId == "" {
+		return true
+	}
+	if _, appErr := h.storage.GetEscalationPolicy(ctx, projectId, policyId); appErr != nil {
+		if appErr.Type == errs.TypeInternal {
+			h.internalError(w)
+			return false
+		}
+		h.error(w, http.StatusBadRequest, appErr.Msg)
+		return false
+	}
+	return true
+}
+
+// Check that channels and schedules of policy steps belong to project. Writes error response if they don't
+func (h *HTTPHandler) checkEscalationTargetsExist(ctx context.Context, w http.ResponseWriter, p *domain.EscalationPolicy) bool {
+	channels, appErr := h.storage.GetNotificationChannels(ctx, p.ProjectId)
+	if appErr != nil {
+		h.internalError(w)
+		return false
+	}
+	schedules, appErr := h.storage.GetSchedules(ctx, p.ProjectId)
+	if appErr != nil {
+		h.internalError(w)
+		return false
+	}
+	knownChannels := make(map[string]bool, len(channels))
+	for _, ch := range channels {
+		knownChannels[ch.ID] = true
+	}
+	knownSchedules := make(map[string]bool, len(schedules))
+	for _, s := range schedules {
+		knownSchedules[s.ID] = true
+	}
+	for i, step := range p.Steps {
+		for _, id := range step.ChannelIDs {
+			if !knownChannels[id] {
+				h.error(w, http.StatusBadRequest, fmt.Sprintf("step %d: notification channel not found: id=%s", i+1, id))
+				return false
+			}
+		}
+		for _, id := range step.ScheduleIDs {
+			if !knownSchedules[id] {
+				h.error(w, http.StatusBadRequest, fmt.Sprintf("step %d: on-call schedule not found: id=%s", i+1, id))
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Parse `from` and `to` query params in RFC3339 format.
+// `to` defaults to now, `from` defaults to `to` minus `defWindow`
+func (h *HTTPHandler) parseTimeRange(r *http.Request, defWindow time.Duration) (from, to time.Time, err error) {
+	to = time.Now()
+	if toStr := r.URL.Query().Get("to"); toStr != "" {
+		if to, err = time.Parse(time.RFC3339, toStr); err != nil {
+			return from, to, errors.New("to must be in RFC3339 format")
+		}
+	}
+	from = to.Add(-defWindow)
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		if from, err = time.Parse(time.RFC3339, fromStr); err != nil {
+			return from, to, errors.New("from must be in RFC3339 format")
+		}
+	}
+	if from.After(to) {
+		return from, to, errors.New("from must be before to")
+	}
+	return from, to, nil
+}
+
+// Parse `window` query param (e.g. `24h`, `7d`, `30d`) as range ending now.
+// Falls back to `from` and `to` query params if window is not set.
+// Range cannot be longer than history retention
+func (h *HTTPHandler) parseWindow(r *http.Request, defWindow time.Duration) (from, to time.Time, err error) {
+	windowStr := r.URL.Query().Get("window")
+	if windowStr == "" {
+		if from, to, err = h.parseTimeRange(r, defWindow); err != nil {
+			return from, to, err
+		}
+	} else {
+		window, err := h.parseDuration(windowStr)
+		if err != nil {
+			return from, to, errors.New("invalid window")
+		}
+		if window <= 0 {
+			return from, to, errors.New("window must be positive")
+		}
+		to = time.Now()
+		from = to.Add(-window)
+	}
+
+	if to.Sub(from) > h.historyRetention {
+		return from, to, fmt.Errorf("window cannot be longer than history retention of %dd", h.historyRetention/(24*time.Hour))
+	}
+	return from, to, nil
+}
+
+// Parse duration in Go format or as amount of days (e.g. `7d`)
+func (h *HTTPHandler) parseDuration(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// Parse `buckets` query param: comma separated histogram upper bounds in milliseconds.
+// Returns nil if param is not set
+func (h *HTTPHandler) parseLatencyBuckets(r *http.Request) ([]time.Duration, error) {
+	bucketsStr := r.URL.Query().Get("buckets")
+	if bucketsStr == "" {
+		return nil, nil
+	}
+
+	var buckets []time.Duration
+	for _, v := range strings.Split(bucketsStr, ",") {
+		ms, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil || ms <= 0 {
+			return nil, errors.New("buckets must be comma separated positive numbers of milliseconds")
+		}
+		buckets = append(buckets, time.Duration(ms*float64(time.Millisecond)))
+	}
+	if len(buckets) > maxLatencyBuckets {
+		return nil, fmt.Errorf("no more than %d buckets allowed", maxLatencyBuckets)
+	}
+	return buckets, nil
+}
+
+// Parse `limit` and `offset` query params.
+// `limit` defaults to `defLimit` and cannot be greater than `maxLimit`
+func (h *HTTPHandler) parseLimitOffset(r *http.Request, defLimit, maxLimit int64) (limit, offset int64, err error) {
+	limit = defLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err = strconv.ParseInt(limitStr, 10, 64)
+		if err != nil || limit <= 0 || limit > maxLimit {
+			return 0, 0, fmt.Errorf("limit must be in range 1-%d", maxLimit)
+		}
+	}
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		offset, err = strconv.ParseInt(offsetStr, 10, 64)
+		if err != nil || offset < 0 {
+			return 0, 0, errors.New("offset must be non-negative integer")
+		}
+	}
+	return limit, offset, nil
+}
+
+// Write single metric in prometheus text format
+func (h *HTTPHandler) writeMetric(w io.Writer, name, metricType, help string, value int64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %d\n", name, help, name, metricType, name, value)
 }
 
 func (h *HTTPHandler) domainEndpointToDTO(ep *domain.Endpoint) *EndpointResponse {
 	return &EndpointResponse{
-		ID:           ep.ID,
-		Name:         ep.Name,
-		URL:          ep.URL,
-		Status:       ep.Status,
-		LastChecked:  ep.LastChecked,
-		ResponseTime: ep.ResponseTime,
+		ID:                   ep.ID,
+		EndpointConfig:       h.domainEndpointConfigToDTO(&ep.EndpointInfo),
+		HeartbeatToken:       ep.HeartbeatToken,
+		LastChecked:          h.formatLastChecked(ep.LastChecked),
+		CheckResultResponse:  h.domainCheckResultToDTO(&ep.CheckResult),
+		ConfirmationResponse: h.domainConfirmationToDTO(&ep.Confirmation, ep.State),
+		FlappingResponse:     h.domainFlapDetectionToDTO(&ep.FlapDetection),
+		IncidentID:           ep.IncidentID,
 	}
 }
 
 func (h *HTTPHandler) domainEndpointStatusToDTO(ep *domain.EndpointStatus) *EndpointStatusResponse {
 	return &EndpointStatusResponse{
-		ID:           ep.ID,
-		Status:       ep.Status,
-		LastChecked:  ep.LastChecked,
-		ResponseTime: ep.ResponseTime,
+		ID:                   ep.ID,
+		LastChecked:          h.formatLastChecked(ep.LastChecked),
+		CheckResultResponse:  h.domainCheckResultToDTO(&ep.CheckResult),
+		ConfirmationResponse: h.domainConfirmationToDTO(&ep.Confirmation, ep.State),
+		FlappingResponse:     h.domainFlapDetectionToDTO(&ep.FlapDetection),
+		IncidentID:           ep.IncidentID,
+	}
+}
+
+func (h *HTTPHandler) domainFlapDetectionToDTO(f *domain.FlapDetection) FlappingResponse {
+	return FlappingResponse{
+		Flapping:  f.IsFlapping,
+		FlapScore: f.FlapScore,
+	}
+}
+
+func (h *HTTPHandler) domainConfirmationToDTO(c *domain.Confirmation, state domain.State) ConfirmationResponse {
+	return ConfirmationResponse{
+		CheckState:           string(c.CheckState),
+		ConsecutiveFailures:  c.Failures,
+		ConsecutiveSuccesses: c.Successes,
+		StateSince:           h.formatLastChecked(c.StateSince),
+		Confirming:           c.Confirming(state),
+	}
+}
+
+func (h *HTTPHandler) domainCheckResultToDTO(res *domain.CheckResult) CheckResultResponse {
+	return CheckResultResponse{
+		State:       string(res.State),
+		StatusCode:  res.StatusCode,
+		ErrorClass:  string(res.ErrorClass),
+		Error:       res.Error,
+		DurationMs:  domain.Milliseconds(res.Duration),
+		HandshakeMs: domain.Milliseconds(res.Handshake),
+		RoundTripMs: domain.Milliseconds(res.RoundTrip),
+		Certificate: h.domainCertInfoToDTO(res.Cert),
+	}
+}
+
+func (h *HTTPHandler) domainCertInfoToDTO(cert *domain.CertInfo) *CertificateResponse {
+	if cert == nil {
+		return nil
+	}
+	return &CertificateResponse{
+		NotAfter:   cert.NotAfter.Format(time.RFC3339),
+		Issuer:     cert.Issuer,
+		Subject:    cert.Subject,
+		SANs:       cert.SANs,
+		ChainValid: cert.ChainValid,
+		ChainError: cert.ChainError,
+		DaysLeft:   int(time.Until(cert.NotAfter).Hours() / 24),
 	}
 }
 
+// Format time in RFC3339, zero time (never checked) is formatted as empty string
+func (h *HTTPHandler) formatLastChecked(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
 func (h *HTTPHandler) domainEndpointInfoToDTO(ep *domain.EndpointInfo) *EndpointInfoResponse {
 	return &EndpointInfoResponse{
-		ID:   ep.ID,
-		Name: ep.Name,
-		URL:  ep.URL,
+		ID:             ep.ID,
+		EndpointConfig: h.domainEndpointConfigToDTO(ep),
+		HeartbeatToken: ep.HeartbeatToken,
+	}
+}
+
+func (h *HTTPHandler) domainEndpointConfigToDTO(ep *domain.EndpointInfo) EndpointConfig {
+	cfg := EndpointConfig{
+		Name:                   ep.Name,
+		Type:                   string(ep.Type),
+		URL:                    ep.URL,
+		SLOTarget:              ep.SLOTarget,
+		EscalationPolicyID:     ep.EscalationPolicyID,
+		IntervalSec:            int64(ep.Interval.Seconds()),
+		TimeoutMs:              ep.Timeout.Milliseconds(),
+		Retries:                ep.Retries,
+		FailureThreshold:       ep.FailureThreshold,
+		RecoveryThreshold:      ep.RecoveryThreshold,
+		RecheckIntervalSec:     int64(ep.RecheckInterval.Seconds()),
+		Method:                 ep.Method,
+		Headers:                make([]*HeaderRequest, len(ep.Headers)),
+		Body:                   ep.Body,
+		ExpectedStatuses:       ep.ExpectedStatuses.String(),
+		RedirectPolicy:         ep.RedirectPolicy,
+		Assertions:             make([]*AssertionRequest, len(ep.Assertions)),
+		TCPPayload:             ep.TCPPayload,
+		TCPExpectBanner:        ep.TCPExpectBanner,
+		DNSRecordType:          ep.DNSRecordType,
+		DNSResolver:            ep.DNSResolver,
+		DNSExpected:            ep.DNSExpected,
+		GRPCService:            ep.GRPCService,
+		GRPCTLS:                ep.GRPCTLS,
+		WSMessage:              ep.WSMessage,
+		WSExpectPattern:        ep.WSExpectPattern,
+		HeartbeatPeriodSec:     int64(ep.HeartbeatPeriod.Seconds()),
+		HeartbeatGraceSec:      int64(ep.HeartbeatGrace.Seconds()),
+		TLSExpiryThresholdDays: ep.TLSExpiryThresholdDays,
+	}
+	for i, v := range ep.Headers {
+		cfg.Headers[i] = &HeaderRequest{Name: v.Name, Value: v.Value}
+	}
+	for i, v := range ep.Assertions {
+		cfg.Assertions[i] = &AssertionRequest{
+			Type:     string(v.Type),
+			Target:   v.Target,
+			Operator: v.Operator,
+			Value:    v.Value,
+		}
+	}
+	return cfg
+}
+
+// Set configuration fields of `ep` from request and validate result
+func (h *HTTPHandler) endpointConfigToDomain(cfg *EndpointConfig, ep *domain.EndpointInfo) error {
+	ep.Name = cfg.Name
+	ep.Type = domain.CheckType(cfg.Type)
+	ep.URL = cfg.URL
+	ep.SLOTarget = cfg.SLOTarget
+	ep.EscalationPolicyID = strings.TrimSpace(cfg.EscalationPolicyID)
+	ep.Interval = time.Duration(cfg.IntervalSec) * time.Second
+	ep.Timeout = time.Duration(cfg.TimeoutMs) * time.Millisecond
+	ep.Retries = cfg.Retries
+	ep.FailureThreshold = cfg.FailureThreshold
+	ep.RecoveryThreshold = cfg.RecoveryThreshold
+	ep.RecheckInterval = time.Duration(cfg.RecheckIntervalSec) * time.Second
+	ep.Method = strings.ToUpper(cfg.Method)
+	ep.Body = cfg.Body
+	ep.RedirectPolicy = cfg.RedirectPolicy
+	ep.TCPPayload = cfg.TCPPayload
+	ep.TCPExpectBanner = cfg.TCPExpectBanner
+	ep.DNSRecordType = strings.ToUpper(cfg.DNSRecordType)
+	ep.DNSResolver = cfg.DNSResolver
+	ep.DNSExpected = cfg.DNSExpected
+	ep.GRPCService = cfg.GRPCService
+	ep.GRPCTLS = cfg.GRPCTLS
+	ep.WSMessage = cfg.WSMessage
+	ep.WSExpectPattern = cfg.WSExpectPattern
+	ep.HeartbeatPeriod = time.Duration(cfg.HeartbeatPeriodSec) * time.Second
+	ep.HeartbeatGrace = time.Duration(cfg.HeartbeatGraceSec) * time.Second
+	ep.TLSExpiryThresholdDays = cfg.TLSExpiryThresholdDays
+
+	ep.Headers = make([]domain.Header, 0, len(cfg.Headers))
+	for _, v := range cfg.Headers {
+		if v == nil {
+			continue
+		}
+		ep.Headers = append(ep.Headers, domain.Header{Name: v.Name, Value: v.Value})
+	}
+	ep.Assertions = make([]domain.Assertion, 0, len(cfg.Assertions))
+	for _, v := range cfg.Assertions {
+		if v == nil {
+			continue
+		}
+		ep.Assertions = append(ep.Assertions, domain.Assertion{
+			Type:     domain.AssertionType(v.Type),
+			Target:   v.Target,
+			Operator: v.Operator,
+			Value:    v.Value,
+		})
+	}
+
+	var err error
+	if ep.ExpectedStatuses, err = domain.ParseStatusRanges(cfg.ExpectedStatuses); err != nil {
+		return err
+	}
+
+	return ep.Validate()
+}
+
+func (h *HTTPHandler) domainEndpointCheckToDTO(check *domain.EndpointCheck) *EndpointCheckResponse {
+	return &EndpointCheckResponse{
+		CheckedAt:           check.CheckedAt.Format(time.RFC3339),
+		CheckResultResponse: h.domainCheckResultToDTO(&check.CheckResult),
+	}
+}
+
+func (h *HTTPHandler) domainUptimeToDTO(u *domain.Uptime) *UptimeResponse {
+	return &UptimeResponse{
+		From:                u.From.Format(time.RFC3339),
+		To:                  u.To.Format(time.RFC3339),
+		TotalChecks:         u.TotalChecks,
+		UpChecks:            u.UpChecks,
+		UptimePercent:       u.UptimePercent,
+		SLOTarget:           u.SLOTarget,
+		ErrorBudgetSec:      u.ErrorBudget.Seconds(),
+		DowntimeSec:         u.Downtime.Seconds(),
+		ErrorBudgetConsumed: u.ErrorBudgetConsumed,
+	}
+}
+
+func (h *HTTPHandler) domainLatencyStatsToDTO(st *domain.LatencyStats) *LatencyResponse {
+	resp := &LatencyResponse{
+		From:      st.From.Format(time.RFC3339),
+		To:        st.To.Format(time.RFC3339),
+		Count:     st.Count,
+		MinMs:     domain.Milliseconds(st.Min),
+		MaxMs:     domain.Milliseconds(st.Max),
+		MeanMs:    domain.Milliseconds(st.Mean),
+		P50Ms:     domain.Milliseconds(st.P50),
+		P90Ms:     domain.Milliseconds(st.P90),
+		P95Ms:     domain.Milliseconds(st.P95),
+		P99Ms:     domain.Milliseconds(st.P99),
+		Histogram: make([]*LatencyBucketResponse, len(st.Histogram)),
+	}
+	for i, b := range st.Histogram {
+		resp.Histogram[i] = &LatencyBucketResponse{
+			UpperBoundMs: domain.Milliseconds(b.UpperBound),
+			Count:        b.Count,
+		}
+	}
+	return resp
+}
+
+// Secrets are left out of response
+func (h *HTTPHandler) domainChannelToDTO(ch *domain.NotificationChannel) *ChannelResponse {
+	cfg := h.domainChannelToConfig(ch)
+	cfg.WebhookSecret, cfg.SMTPPassword, cfg.TelegramBotToken = "", "", ""
+	return &ChannelResponse{
+		ID:                  ch.ID,
+		ChannelConfig:       cfg,
+		HasWebhookSecret:    ch.WebhookSecret != "",
+		HasSMTPPassword:     ch.SMTPPassword != "",
+		HasTelegramBotToken: ch.TelegramBotToken != "",
+	}
+}
+
+// Full channel config with secrets, base of partial update
+func (h *HTTPHandler) domainChannelToConfig(ch *domain.NotificationChannel) ChannelConfig {
+	return ChannelConfig{
+		Name:           ch.Name,
+		Type:           string(ch.Type),
+		Enabled:        ch.Enabled,
+		WebhookURL:     ch.WebhookURL,
+		WebhookSecret:  ch.WebhookSecret,
+		SMTPHost:       ch.SMTPHost,
+		SMTPPort:       ch.SMTPPort,
+		SMTPStartTLS:   ch.SMTPStartTLS,
+		SMTPUsername:   ch.SMTPUsername,
+		SMTPPassword:   ch.SMTPPassword,
+		SMTPFrom:       ch.SMTPFrom,
+		SMTPRecipients: ch.SMTPRecipients,
+		// telegram
+		TelegramAPIURL:   ch.TelegramAPIURL,
+		TelegramBotToken: ch.TelegramBotToken,
+		TelegramChatID:   ch.TelegramChatID,
+	}
+}
+
+// Response of created or updated channel, webhook secret is returned only if it was generated
+func (h *HTTPHandler) savedChannelToDTO(cfg *ChannelConfig, ch *domain.NotificationChannel) *ChannelResponse {
+	resp := h.domainChannelToDTO(ch)
+	if ch.WebhookSecret != cfg.WebhookSecret {
+		resp.WebhookSecret = ch.WebhookSecret
+	}
+	return resp
+}
+
+// Fill notification channel from request config and validate it.
+// Missing webhook secret is generated
+func (h *HTTPHandler) channelConfigToDomain(cfg *ChannelConfig, ch *domain.NotificationChannel) error {
+	ch.Name = strings.TrimSpace(cfg.Name)
+	ch.Type = domain.ChannelType(cfg.Type)
+	ch.Enabled = cfg.Enabled
+	ch.WebhookURL = strings.TrimSpace(cfg.WebhookURL)
+	ch.WebhookSecret = cfg.WebhookSecret
+	if ch.ChannelType() == domain.ChannelTypeWebhook && ch.WebhookSecret == "" {
+		ch.WebhookSecret = uuid.NewString()
+	}
+	ch.SMTPHost = strings.TrimSpace(cfg.SMTPHost)
+	ch.SMTPPort = cfg.SMTPPort
+	ch.SMTPStartTLS = cfg.SMTPStartTLS
+	ch.SMTPUsername = cfg.SMTPUsername
+	ch.SMTPPassword = cfg.SMTPPassword
+	ch.SMTPFrom = strings.TrimSpace(cfg.SMTPFrom)
+	ch.SMTPRecipients = cfg.SMTPRecipients
+	ch.TelegramAPIURL = strings.TrimRight(strings.TrimSpace(cfg.TelegramAPIURL), "/")
+	ch.TelegramBotToken = strings.TrimSpace(cfg.TelegramBotToken)
+	ch.TelegramChatID = strings.TrimSpace(cfg.TelegramChatID)
+	return ch.Validate()
+}
+
+func (h *HTTPHandler) domainDeliveryToDTO(d *domain.Delivery) *DeliveryResponse {
+	return &DeliveryResponse{
+		ID:         d.ID,
+		Event:      string(d.Event),
+		EndpointID: d.EndpointID,
+		Attempt:    d.Attempt,
+		At:         d.At.Format(time.RFC3339),
+		DurationMs: domain.Milliseconds(d.Duration),
+		StatusCode: d.StatusCode,
+		Error:      d.Error,
+		Success:    d.Success,
+	}
+}
+
+func (h *HTTPHandler) domainEscalationPolicyToDTO(p *domain.EscalationPolicy) *EscalationPolicyResponse {
+	resp := &EscalationPolicyResponse{
+		ID: p.ID,
+		EscalationPolicyConfig: EscalationPolicyConfig{
+			Name:  p.Name,
+			Steps: make([]*EscalationStepRequest, len(p.Steps)),
+		},
+	}
+	for i, step := range p.Steps {
+		resp.Steps[i] = &EscalationStepRequest{
+			DelaySec:    int64(step.Delay.Seconds()),
+			ChannelIDs:  step.ChannelIDs,
+			ScheduleIDs: step.ScheduleIDs,
+		}
+	}
+	return resp
+}
+
+// Fill escalation policy from request config and validate it
+func (h *HTTPHandler) escalationPolicyConfigToDomain(cfg *EscalationPolicyConfig, p *domain.EscalationPolicy) error {
+	p.Name = strings.TrimSpace(cfg.Name)
+	p.Steps = make([]domain.EscalationStep, 0, len(cfg.Steps))
+	for _, step := range cfg.Steps {
+		if step == nil {
+			continue
+		}
+		ids := make([]string, 0, len(step.ChannelIDs))
+		for _, id := range step.ChannelIDs {
+			ids = append(ids, strings.TrimSpace(id))
+		}
+		var scheduleIds []string
+		for _, id := range step.ScheduleIDs {
+			scheduleIds = append(scheduleIds, strings.TrimSpace(id))
+		}
+		p.Steps = append(p.Steps, domain.EscalationStep{
+			Delay:       time.Duration(step.DelaySec) * time.Second,
+			ChannelIDs:  ids,
+			ScheduleIDs: scheduleIds,
+		})
+	}
+	return p.Validate()
+}
+
+// Times are shown in schedule time zone
+func (h *HTTPHandler) domainScheduleToDTO(s *domain.OnCallSchedule) *ScheduleResponse {
+	loc, err := s.Location()
+	if err != nil {
+		loc = time.UTC
+	}
+	minutes := int(s.HandoffTime / time.Minute)
+	resp := &ScheduleResponse{
+		ID: s.ID,
+		ScheduleConfig: ScheduleConfig{
+			Name:         s.Name,
+			Timezone:     s.Timezone,
+			Rotation:     string(s.Rotation),
+			HandoffTime:  fmt.Sprintf("%02d:%02d", minutes/60, minutes%60),
+			HandoffDay:   strings.ToLower(s.HandoffWeekday.String()),
+			Start:        s.Start.In(loc).Format(time.RFC3339),
+			Participants: make([]*ParticipantRequest, len(s.Participants)),
+		},
+	}
+	for i, p := range s.Participants {
+		resp.Participants[i] = &ParticipantRequest{
+			Name:  p.Name,
+			Email: p.Email,
+		}
+	}
+	return resp
+}
+
+// Fill on-call schedule from request config and validate it
+func (h *HTTPHandler) scheduleConfigToDomain(cfg *ScheduleConfig, s *domain.OnCallSchedule) error {
+	s.Name = strings.TrimSpace(cfg.Name)
+	s.Timezone = strings.TrimSpace(cfg.Timezone)
+	s.Rotation = domain.RotationType(strings.ToLower(strings.TrimSpace(cfg.Rotation)))
+
+	handoff, err := time.Parse("15:04", strings.TrimSpace(cfg.HandoffTime))
+	if err != nil {
+		return errors.New("handoff_time must be in HH:MM format")
+	}
+	s.HandoffTime = time.Duration(handoff.Hour())*time.Hour + time.Duration(handoff.Minute())*time.Minute
+
+	day, ok := weekdays[strings.ToLower(strings.TrimSpace(cfg.HandoffDay))]
+	if !ok {
+		return errors.New("handoff_day must be a weekday name, e.g. monday")
+	}
+	s.HandoffWeekday = day
+
+	if s.Start, err = time.Parse(time.RFC3339, cfg.Start); err != nil {
+		return errors.New("start must be in RFC3339 format")
+	}
+
+	s.Participants = make([]domain.OnCallParticipant, 0, len(cfg.Participants))
+	for _, p := range cfg.Participants {
+		if p == nil {
+			continue
+		}
+		s.Participants = append(s.Participants, domain.OnCallParticipant{
+			Name:  strings.TrimSpace(p.Name),
+			Email: strings.TrimSpace(p.Email),
+		})
+	}
+	return s.Validate()
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+func (h *HTTPHandler) domainOverrideToDTO(o *domain.OnCallOverride) *OverrideResponse {
+	return &OverrideResponse{
+		ID: o.ID,
+		OverrideRequest: OverrideRequest{
+			Start: o.Start.Format(time.RFC3339),
+			End:   o.End.Format(time.RFC3339),
+			ParticipantRequest: ParticipantRequest{
+				Name:  o.Participant.Name,
+				Email: o.Participant.Email,
+			},
+		},
+	}
+}
+
+// Build override from request and validate it
+func (h *HTTPHandler) overrideRequestToDomain(req *OverrideRequest) (*domain.OnCallOverride, error) {
+	o := &domain.OnCallOverride{
+		Participant: domain.OnCallParticipant{
+			Name:  strings.TrimSpace(req.Name),
+			Email: strings.TrimSpace(req.Email),
+		},
+	}
+	var err error
+	if o.Start, err = time.Parse(time.RFC3339, req.Start); err != nil {
+		return nil, errors.New("start must be in RFC3339 format")
+	}
+	if o.End, err = time.Parse(time.RFC3339, req.End); err != nil {
+		return nil, errors.New("end must be in RFC3339 format")
+	}
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	if !o.End.After(time.Now()) {
+		return nil, errors.New("end must be in the future")
+	}
+	return o, nil
+}
+
+// Shift of schedule at `at` in schedule time zone, response has no participant if nobody is on call
+func (h *HTTPHandler) domainShiftToDTO(s *domain.OnCallSchedule, at time.Time, shift *domain.OnCallShift) *OnCallResponse {
+	loc, err := s.Location()
+	if err != nil {
+		loc = time.UTC
+	}
+	resp := &OnCallResponse{
+		ScheduleID: s.ID,
+		At:         at.In(loc).Format(time.RFC3339),
+	}
+	if shift == nil {
+		return resp
+	}
+	resp.Participant = &ParticipantRequest{
+		Name:  shift.Participant.Name,
+		Email: shift.Participant.Email,
+	}
+	resp.ShiftStart = shift.Start.In(loc).Format(time.RFC3339)
+	resp.ShiftEnd = shift.End.In(loc).Format(time.RFC3339)
+	if shift.Override != nil {
+		resp.OverrideID = shift.Override.ID
+	}
+	return resp
+}
+
+func (h *HTTPHandler) domainEscalationToDTO(e *domain.Escalation) *EscalationResponse {
+	return &EscalationResponse{
+		PolicyID: e.PolicyID,
+		NextStep: e.Step + 1,
+		NextAt:   e.DueAt.Format(time.RFC3339),
+		Rounds:   e.Round,
+	}
+}
+
+// Timeline is added only if it is not nil
+func (h *HTTPHandler) domainIncidentToDTO(inc *domain.Incident, timeline []*domain.TimelineEntry) *IncidentResponse {
+	resp := &IncidentResponse{
+		ID:             inc.ID,
+		EndpointID:     inc.EndpointID,
+		EndpointName:   inc.EndpointName,
+		State:          string(inc.State),
+		Cause:          inc.Cause,
+		OpenedAt:       inc.OpenedAt.Format(time.RFC3339),
+		AcknowledgedBy: inc.AcknowledgedBy,
+		ResolvedBy:     inc.ResolvedBy,
+		DurationSec:    inc.Duration(time.Now()).Seconds(),
+	}
+	if !inc.AcknowledgedAt.IsZero() {
+		resp.AcknowledgedAt = inc.AcknowledgedAt.Format(time.RFC3339)
+	}
+	if !inc.ResolvedAt.IsZero() {
+		resp.ResolvedAt = inc.ResolvedAt.Format(time.RFC3339)
+	}
+	if timeline != nil {
+		resp.Timeline = make([]*TimelineEntryResponse, len(timeline))
+		for i, e := range timeline {
+			resp.Timeline[i] = h.domainTimelineEntryToDTO(e)
+		}
+	}
+	return resp
+}
+
+func (h *HTTPHandler) domainTimelineEntryToDTO(e *domain.TimelineEntry) *TimelineEntryResponse {
+	return &TimelineEntryResponse{
+		At:      e.At.Format(time.RFC3339),
+		Type:    string(e.Type),
+		State:   e.State,
+		Message: e.Message,
+		Author:  e.Author,
+	}
+}
+
+// Parse `state` (comma separated) and `endpoint_id` query params of incidents list
+func (h *HTTPHandler) parseIncidentFilter(r *http.Request) (*domain.IncidentFilter, error) {
+	filter := &domain.IncidentFilter{
+		EndpointID: strings.TrimSpace(r.URL.Query().Get("endpoint_id")),
+	}
+	if v := r.URL.Query().Get("state"); v != "" {
+		for _, st := range strings.Split(v, ",") {
+			state := domain.IncidentState(strings.TrimSpace(st))
+			if !state.Valid() {
+				return nil, fmt.Errorf("unknown incident state: %s", st)
+			}
+			filter.States = append(filter.States, state)
+		}
 	}
+	return filter, nil
 }
@@ -0,0 +1,103 @@
+// Minimal JSONPath subset: root `$` followed by `.key`, `['key']` and `[index]` steps.
+// Negative index counts from the end of array
+package jsonpath
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrNotFound = errors.New("path not found")
+
+type step struct {
+	key   string
+	index int
+	isIdx bool
+}
+
+type Path struct {
+	expr  string
+	steps []step
+}
+
+func Parse(expr string) (*Path, error) {
+	if !strings.HasPrefix(expr, "$") {
+		return nil, fmt.Errorf("jsonpath must start with $: %s", expr)
+	}
+	p := &Path{expr: expr}
+
+	rest := expr[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("empty key in jsonpath: %s", expr)
+			}
+			p.steps = append(p.steps, step{key: rest[:end]})
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("unclosed bracket in jsonpath: %s", expr)
+			}
+			inner := rest[1:end]
+			rest = rest[end+1:]
+
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				p.steps = append(p.steps, step{key: inner[1 : len(inner)-1]})
+				continue
+			}
+			idx, err := strconv.Atoi(inner)
+			if err != nil {
+				return nil, fmt.Errorf("invalid index in jsonpath: %s", expr)
+			}
+			p.steps = append(p.steps, step{index: idx, isIdx: true})
+		default:
+			return nil, fmt.Errorf("unexpected character %q in jsonpath: %s", rest[0], expr)
+		}
+	}
+
+	return p, nil
+}
+
+// Get value at path from document decoded by `encoding/json` into `any`
+func (p *Path) Get(doc any) (any, error) {
+	cur := doc
+	for _, s := range p.steps {
+		if s.isIdx {
+			arr, ok := cur.([]any)
+			if !ok {
+				return nil, ErrNotFound
+			}
+			i := s.index
+			if i < 0 {
+				i += len(arr)
+			}
+			if i < 0 || i >= len(arr) {
+				return nil, ErrNotFound
+			}
+			cur = arr[i]
+			continue
+		}
+
+		obj, ok := cur.(map[string]any)
+		if !ok {
+			return nil, ErrNotFound
+		}
+		if cur, ok = obj[s.key]; !ok {
+			return nil, ErrNotFound
+		}
+	}
+	return cur, nil
+}
+
+func (p *Path) String() string {
+	return p.expr
+}
@@ -0,0 +1,108 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    []step
+		wantErr bool
+	}{
+		{"$", nil, false},
+		{"$.a", []step{{key: "a"}}, false},
+		{"$.a.b", []step{{key: "a"}, {key: "b"}}, false},
+		{"$['a.b']", []step{{key: "a.b"}}, false},
+		{`$["a b"].c`, []step{{key: "a b"}, {key: "c"}}, false},
+		{"$.items[0].id", []step{{key: "items"}, {index: 0, isIdx: true}, {key: "id"}}, false},
+		{"$[-1][2]", []step{{index: -1, isIdx: true}, {index: 2, isIdx: true}}, false},
+		{"a.b", nil, true},
+		{"", nil, true},
+		{"$.", nil, true},
+		{"$..a", nil, true},
+		{"$.a[0", nil, true},
+		{"$[x]", nil, true},
+		{"$['a\"]", nil, true},
+		{"$a", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			p, err := Parse(tt.expr)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got steps %+v, want error", p.steps)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(p.steps, tt.want) || p.String() != tt.expr {
+				t.Fatalf("got steps %+v of %q, want %+v", p.steps, p, tt.want)
+			}
+		})
+	}
+}
+
+func TestGet(t *testing.T) {
+	var doc any
+	body := `{"status": "ok", "count": 3, "a.b": true, "items": [{"id": 1}, {"id": 2, "tags": ["x", "y"]}], "empty": null}`
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		expr string
+		want any
+		// ErrNotFound expected
+		missing bool
+	}{
+		{"$.status", "ok", false},
+		{"$.count", float64(3), false},
+		{"$['a.b']", true, false},
+		{"$.items[0].id", float64(1), false},
+		{"$.items[1].tags[1]", "y", false},
+		{"$.items[-1].id", float64(2), false},
+		{"$.items[-2].id", float64(1), false},
+		{"$.empty", nil, false},
+		{"$.missing", nil, true},
+		{"$.items[2]", nil, true},
+		{"$.items[-3]", nil, true},
+		{"$.items[0].tags", nil, true},
+		// index of object and key of array
+		{"$[0]", nil, true},
+		{"$.items.id", nil, true},
+		{"$.status.len", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			p, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := p.Get(doc)
+			if tt.missing {
+				if !errors.Is(err, ErrNotFound) {
+					t.Fatalf("got %v, err %v; want ErrNotFound", got, err)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %v, err %v; want %v", got, err, tt.want)
+			}
+		})
+	}
+
+	t.Run("root", func(t *testing.T) {
+		p, _ := Parse("$")
+		if got, err := p.Get(doc); err != nil || !reflect.DeepEqual(got, doc) {
+			t.Fatalf("got %v, err %v; want whole document", got, err)
+		}
+	})
+}
@@ -0,0 +1,165 @@
+package monitor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/wrtgvr/websites-monitor/internal/domain"
+	"github.com/wrtgvr/websites-monitor/internal/jsonpath"
+)
+
+// Check assertions in order. Returns error naming first failed assertion.
+// `truncated` means body was cut at max body size, so missing value proves nothing
+func checkAssertions(assertions []domain.Assertion, header http.Header, body []byte, truncated bool) error {
+	// body is decoded once, only if some assertion needs it
+	var doc any
+	var docErr error
+	decoded := false
+
+	for i := range assertions {
+		a := &assertions[i]
+
+		var err error
+		switch a.Type {
+		case domain.AssertBodyContains:
+			if !bytes.Contains(body, []byte(a.Value)) {
+				err = fmt.Errorf("body does not contain value")
+			}
+		case domain.AssertBodyNotContains:
+			if bytes.Contains(body, []byte(a.Value)) {
+				err = fmt.Errorf("body contains value")
+			} else if truncated {
+				err = fmt.Errorf("body is larger than %d bytes, rest of it is not checked", len(body))
+			}
+		case domain.AssertBodyRegex:
+			err = matchRegex(a, body, "body")
+		case domain.AssertHeader:
+			values := header.Values(a.Target)
+			if len(values) == 0 {
+				err = fmt.Errorf("header is missing")
+				break
+			}
+			// repeated header passes if any of its values matches
+			for _, v := range values {
+				if err = matchRegex(a, []byte(v), "header"); err == nil {
+					break
+				}
+			}
+		case domain.AssertJSONPath:
+			if !decoded {
+				decoded = true
+				dec := json.NewDecoder(bytes.NewReader(body))
+				dec.UseNumber()
+				docErr = dec.Decode(&doc)
+			}
+			if docErr != nil {
+				err = fmt.Errorf("body is not valid json: %v", docErr)
+				break
+			}
+			err = checkJSONPath(a, doc)
+		default:
+			err = fmt.Errorf("unknown assertion type")
+		}
+
+		if err != nil {
+			return fmt.Errorf("assertion failed: %s: %w", a, err)
+		}
+	}
+	return nil
+}
+
+func matchRegex(a *domain.Assertion, v []byte, what string) error {
+	re := a.Regexp()
+	if re == nil {
+		// assertion was not compiled on load
+		var err error
+		if re, err = regexp.Compile(a.Value); err != nil {
+			return fmt.Errorf("invalid regex: %v", err)
+		}
+	}
+	if !re.Match(v) {
+		return fmt.Errorf("%s does not match", what)
+	}
+	return nil
+}
+
+func checkJSONPath(a *domain.Assertion, doc any) error {
+	path, err := jsonpath.Parse(a.Target)
+	if err != nil {
+		return err
+	}
+	got, err := path.Get(doc)
+	if err != nil {
+		return err
+	}
+
+	op := a.Operator
+	if op == "" {
+		op = domain.OpEqual
+	}
+	if op == domain.OpExists {
+		return nil
+	}
+
+	//* numbers are compared numerically
+	if num, ok := got.(json.Number); ok {
+		gotF, err1 := num.Float64()
+		wantF, err2 := strconv.ParseFloat(a.Value, 64)
+		if err1 == nil && err2 == nil {
+			if !compareFloats(op, gotF, wantF) {
+				return fmt.Errorf("got %s", num)
+			}
+			return nil
+		}
+	}
+
+	//* everything else is compared as string
+	var gotStr string
+	switch v := got.(type) {
+	case string:
+		gotStr = v
+	case nil:
+		gotStr = "null"
+	case bool, json.Number:
+		gotStr = fmt.Sprint(v)
+	default:
+		b, _ := json.Marshal(v)
+		gotStr = string(b)
+	}
+
+	var ok bool
+	switch op {
+	case domain.OpEqual:
+		ok = gotStr == a.Value
+	case domain.OpNotEqual:
+		ok = gotStr != a.Value
+	default:
+		return fmt.Errorf("value is not a number: %s", gotStr)
+	}
+	if !ok {
+		return fmt.Errorf("got %q", gotStr)
+	}
+	return nil
+}
+
+func compareFloats(op string, got, want float64) bool {
+	switch op {
+	case domain.OpEqual:
+		return got == want
+	case domain.OpNotEqual:
+		return got != want
+	case domain.OpLess:
+		return got < want
+	case domain.OpLessEqual:
+		return got <= want
+	case domain.OpGreater:
+		return got > want
+	case domain.OpGreaterEqual:
+		return got >= want
+	}
+	return false
+}
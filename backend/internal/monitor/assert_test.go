@@ -0,0 +1,91 @@
+package monitor
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/wrtgvr/websites-monitor/internal/domain"
+)
+
+func TestCheckAssertions(t *testing.T) {
+	body := []byte(`{"status": "ok", "count": 3, "ratio": 0.5, "live": true, "items": [{"id": "a"}], "none": null}`)
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Add("Set-Cookie", "a=1")
+	header.Add("Set-Cookie", "session=abc")
+
+	jsonPath := func(target, op, value string) domain.Assertion {
+		return domain.Assertion{Type: domain.AssertJSONPath, Target: target, Operator: op, Value: value}
+	}
+
+	tests := []struct {
+		name       string
+		assertions []domain.Assertion
+		body       []byte
+		truncated  bool
+		// substring of error, empty means assertions pass
+		wantErr string
+	}{
+		{"no assertions", nil, body, false, ""},
+		{"body contains", []domain.Assertion{{Type: domain.AssertBodyContains, Value: `"ok"`}}, body, false, ""},
+		{"body does not contain", []domain.Assertion{{Type: domain.AssertBodyContains, Value: "fail"}}, body, false, "body does not contain value"},
+		{"body not contains", []domain.Assertion{{Type: domain.AssertBodyNotContains, Value: "fail"}}, body, false, ""},
+		{"body not contains fails", []domain.Assertion{{Type: domain.AssertBodyNotContains, Value: "ok"}}, body, false, "body contains value"},
+		{"body not contains of truncated body", []domain.Assertion{{Type: domain.AssertBodyNotContains, Value: "fail"}}, body, true, "rest of it is not checked"},
+		{"body regex", []domain.Assertion{{Type: domain.AssertBodyRegex, Value: `"count": \d+`}}, body, false, ""},
+		{"body regex does not match", []domain.Assertion{{Type: domain.AssertBodyRegex, Value: `^\[`}}, body, false, "body does not match"},
+		{"invalid regex not compiled on load", []domain.Assertion{{Type: domain.AssertBodyRegex, Value: `(`}}, body, false, "invalid regex"},
+		{"header", []domain.Assertion{{Type: domain.AssertHeader, Target: "content-type", Value: "^application/json"}}, body, false, ""},
+		{"header is missing", []domain.Assertion{{Type: domain.AssertHeader, Target: "X-Trace", Value: ".*"}}, body, false, "header is missing"},
+		{"header does not match", []domain.Assertion{{Type: domain.AssertHeader, Target: "Content-Type", Value: "text/html"}}, body, false, "header does not match"},
+		{"any value of repeated header", []domain.Assertion{{Type: domain.AssertHeader, Target: "Set-Cookie", Value: "^session="}}, body, false, ""},
+		{"no value of repeated header", []domain.Assertion{{Type: domain.AssertHeader, Target: "Set-Cookie", Value: "^token="}}, body, false, "header does not match"},
+		{"eq string", []domain.Assertion{jsonPath("$.status", "", "ok")}, body, false, ""},
+		{"eq string fails", []domain.Assertion{jsonPath("$.status", domain.OpEqual, "down")}, body, false, `got "ok"`},
+		{"ne string", []domain.Assertion{jsonPath("$.status", domain.OpNotEqual, "down")}, body, false, ""},
+		{"ne string fails", []domain.Assertion{jsonPath("$.items[0].id", domain.OpNotEqual, "a")}, body, false, `got "a"`},
+		{"eq number compares numerically", []domain.Assertion{jsonPath("$.count", domain.OpEqual, "3.0")}, body, false, ""},
+		{"ne number", []domain.Assertion{jsonPath("$.count", domain.OpNotEqual, "3")}, body, false, "got 3"},
+		{"lt", []domain.Assertion{jsonPath("$.ratio", domain.OpLess, "1")}, body, false, ""},
+		{"lt fails", []domain.Assertion{jsonPath("$.ratio", domain.OpLess, "0.5")}, body, false, "got 0.5"},
+		{"lte", []domain.Assertion{jsonPath("$.ratio", domain.OpLessEqual, "0.5")}, body, false, ""},
+		{"gt", []domain.Assertion{jsonPath("$.count", domain.OpGreater, "2")}, body, false, ""},
+		{"gt fails", []domain.Assertion{jsonPath("$.count", domain.OpGreater, "3")}, body, false, "got 3"},
+		{"gte", []domain.Assertion{jsonPath("$.count", domain.OpGreaterEqual, "3")}, body, false, ""},
+		{"gte fails", []domain.Assertion{jsonPath("$.count", domain.OpGreaterEqual, "4")}, body, false, "got 3"},
+		{"number against non number value", []domain.Assertion{jsonPath("$.count", domain.OpGreater, "many")}, body, false, "value is not a number: 3"},
+		{"ordering of string", []domain.Assertion{jsonPath("$.status", domain.OpGreater, "1")}, body, false, "value is not a number: ok"},
+		{"exists", []domain.Assertion{jsonPath("$.none", domain.OpExists, "")}, body, false, ""},
+		{"exists fails", []domain.Assertion{jsonPath("$.missing", domain.OpExists, "")}, body, false, "path not found"},
+		{"bool", []domain.Assertion{jsonPath("$.live", domain.OpEqual, "true")}, body, false, ""},
+		{"null", []domain.Assertion{jsonPath("$.none", domain.OpEqual, "null")}, body, false, ""},
+		{"object is compared as json", []domain.Assertion{jsonPath("$.items[0]", domain.OpEqual, `{"id":"a"}`)}, body, false, ""},
+		{"missing key", []domain.Assertion{jsonPath("$.items[1].id", "", "a")}, body, false, "path not found"},
+		{"invalid json", []domain.Assertion{jsonPath("$.status", "", "ok")}, []byte("<html>"), false, "body is not valid json"},
+		{
+			"first failure is reported",
+			[]domain.Assertion{jsonPath("$.status", "", "ok"), {Type: domain.AssertBodyContains, Value: "fail"}, jsonPath("$.count", "", "4")},
+			body, false, `assertion failed: body_contains "fail"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// compiled like on load, invalid regex stays uncompiled
+			for i := range tt.assertions {
+				_ = tt.assertions[i].Compile()
+			}
+			err := checkAssertions(tt.assertions, header, tt.body, tt.truncated)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("got %v, want assertions to pass", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("got %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
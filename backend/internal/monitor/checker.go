@@ -0,0 +1,32 @@
+package monitor
+
+import (
+	"context"
+
+	"github.com/wrtgvr/websites-monitor/internal/domain"
+)
+
+// Performs single check of endpoint.
+// Check must finish when `ctx` is done and report it as timeout
+type Checker interface {
+	Check(ctx context.Context, ep *domain.EndpointInfo) *domain.CheckResult
+}
+
+// Checkers by endpoint check type
+var checkers = map[domain.CheckType]Checker{
+	domain.CheckTypeHTTP: &httpChecker{},
+	domain.CheckTypeTCP:  &tcpChecker{},
+	domain.CheckTypeDNS:  &dnsChecker{},
+	domain.CheckTypeTLS:  &tlsChecker{},
+	domain.CheckTypeGRPC: &grpcChecker{},
+	domain.CheckTypeWS:   &wsChecker{},
+}
+
+// Build down result from error
+func failedResult(class domain.ErrorClass, err error) *domain.CheckResult {
+	return &domain.CheckResult{
+		State:      domain.StateDown,
+		ErrorClass: class,
+		Error:      err.Error(),
+	}
+}
@@ -0,0 +1,131 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/wrtgvr/websites-monitor/internal/domain"
+)
+
+// Resolves endpoint hostname and checks that expected values are among answers
+type dnsChecker struct{}
+
+func (c *dnsChecker) Check(ctx context.Context, ep *domain.EndpointInfo) *domain.CheckResult {
+	resolver := net.DefaultResolver
+	if ep.DNSResolver != "" {
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, ep.DNSResolver)
+			},
+		}
+	}
+
+	start := time.Now()
+
+	answers, err := c.lookup(ctx, resolver, ep.URL, ep.DNSRecordType)
+	if err != nil {
+		class := domain.ErrorClassDNS
+		if classifyError(err) == domain.ErrorClassTimeout {
+			class = domain.ErrorClassTimeout
+		}
+		res := failedResult(class, err)
+		res.Duration = time.Since(start)
+		return res
+	}
+
+	res := &domain.CheckResult{
+		State:    domain.StateUp,
+		Duration: time.Since(start),
+	}
+
+	if len(answers) == 0 {
+		res.State = domain.StateDown
+		res.ErrorClass = domain.ErrorClassDNS
+		res.Error = "no records found"
+		return res
+	}
+
+	//* every expected value must be present
+	got := make(map[string]struct{}, len(answers))
+	for _, a := range answers {
+		got[normalizeDNSValue(ep.DNSRecordType, a)] = struct{}{}
+	}
+	var missing []string
+	for _, v := range ep.DNSExpected {
+		if _, ok := got[normalizeDNSValue(ep.DNSRecordType, v)]; !ok {
+			missing = append(missing, v)
+		}
+	}
+	if len(missing) > 0 {
+		res.State = domain.StateDown
+		res.ErrorClass = domain.ErrorClassAssertion
+		res.Error = fmt.Sprintf("assertion failed: expected records not found: %s, got: %s",
+			strings.Join(missing, ", "), strings.Join(answers, ", "))
+	}
+
+	return res
+}
+
+func (c *dnsChecker) lookup(ctx context.Context, r *net.Resolver, host, recordType string) ([]string, error) {
+	switch recordType {
+	case domain.DNSRecordA, "":
+		return c.lookupIP(ctx, r, "ip4", host)
+	case domain.DNSRecordAAAA:
+		return c.lookupIP(ctx, r, "ip6", host)
+	case domain.DNSRecordCNAME:
+		cname, err := r.LookupCNAME(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		// host itself is returned if it has no CNAME record
+		if normalizeDNSValue(recordType, cname) == normalizeDNSValue(recordType, host) {
+			return nil, nil
+		}
+		return []string{cname}, nil
+	case domain.DNSRecordMX:
+		mxs, err := r.LookupMX(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		answers := make([]string, len(mxs))
+		for i, mx := range mxs {
+			answers[i] = mx.Host
+		}
+		return answers, nil
+	case domain.DNSRecordTXT:
+		return r.LookupTXT(ctx, host)
+	}
+	return nil, fmt.Errorf("unknown dns record type: %s", recordType)
+}
+
+func (c *dnsChecker) lookupIP(ctx context.Context, r *net.Resolver, network, host string) ([]string, error) {
+	ips, err := r.LookupIP(ctx, network, host)
+	if err != nil {
+		return nil, err
+	}
+	answers := make([]string, len(ips))
+	for i, ip := range ips {
+		answers[i] = ip.String()
+	}
+	return answers, nil
+}
+
+// IPs are compared in canonical form, hostnames case-insensitively and without trailing dot.
+// TXT values are compared as is
+func normalizeDNSValue(recordType, v string) string {
+	switch recordType {
+	case domain.DNSRecordTXT:
+		return v
+	case domain.DNSRecordCNAME, domain.DNSRecordMX:
+		return strings.ToLower(strings.TrimSuffix(v, "."))
+	}
+	if ip := net.ParseIP(v); ip != nil {
+		return ip.String()
+	}
+	return v
+}
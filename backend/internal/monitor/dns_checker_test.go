@@ -0,0 +1,163 @@
+package monitor
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wrtgvr/websites-monitor/internal/domain"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// Records served by test dns server, keyed by lowercased fqdn
+var testDNSRecords = map[string][]dnsmessage.Resource{
+	"app.test.": {
+		aRecord("app.test.", [4]byte{192, 0, 2, 1}),
+		aRecord("app.test.", [4]byte{192, 0, 2, 2}),
+	},
+	"v6.test.": {
+		{
+			Header: dnsmessage.ResourceHeader{Name: dnsmessage.MustNewName("v6.test."), Type: dnsmessage.TypeAAAA, Class: dnsmessage.ClassINET, TTL: 60},
+			Body:   &dnsmessage.AAAAResource{AAAA: [16]byte{0x20, 0x01, 0x0d, 0xb8, 15: 1}},
+		},
+	},
+	"alias.test.": {
+		{
+			Header: dnsmessage.ResourceHeader{Name: dnsmessage.MustNewName("alias.test."), Type: dnsmessage.TypeCNAME, Class: dnsmessage.ClassINET, TTL: 60},
+			Body:   &dnsmessage.CNAMEResource{CNAME: dnsmessage.MustNewName("app.test.")},
+		},
+	},
+	"mail.test.": {
+		{
+			Header: dnsmessage.ResourceHeader{Name: dnsmessage.MustNewName("mail.test."), Type: dnsmessage.TypeMX, Class: dnsmessage.ClassINET, TTL: 60},
+			Body:   &dnsmessage.MXResource{Pref: 10, MX: dnsmessage.MustNewName("MX1.Mail.test.")},
+		},
+	},
+	"txt.test.": {
+		{
+			Header: dnsmessage.ResourceHeader{Name: dnsmessage.MustNewName("txt.test."), Type: dnsmessage.TypeTXT, Class: dnsmessage.ClassINET, TTL: 60},
+			Body:   &dnsmessage.TXTResource{TXT: []string{"verify=AbC123"}},
+		},
+	},
+}
+
+func aRecord(name string, ip [4]byte) dnsmessage.Resource {
+	return dnsmessage.Resource{
+		Header: dnsmessage.ResourceHeader{Name: dnsmessage.MustNewName(name), Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET, TTL: 60},
+		Body:   &dnsmessage.AResource{A: ip},
+	}
+}
+
+// Start udp dns server answering from `testDNSRecords`. CNAME records are followed
+// for queries of other types. Returns server address
+func startTestDNSServer(t *testing.T) string {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var req dnsmessage.Message
+			if err := req.Unpack(buf[:n]); err != nil || len(req.Questions) == 0 {
+				continue
+			}
+			resp, err := answerTestDNS(&req).Pack()
+			if err != nil {
+				continue
+			}
+			conn.WriteTo(resp, addr)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func answerTestDNS(req *dnsmessage.Message) *dnsmessage.Message {
+	q := req.Questions[0]
+	resp := &dnsmessage.Message{
+		Header: dnsmessage.Header{
+			ID:                 req.ID,
+			Response:           true,
+			Authoritative:      true,
+			RecursionDesired:   req.RecursionDesired,
+			RecursionAvailable: true,
+		},
+		Questions: req.Questions,
+	}
+
+	name := strings.ToLower(q.Name.String())
+	records, ok := testDNSRecords[name]
+	if !ok {
+		resp.RCode = dnsmessage.RCodeNameError
+		return resp
+	}
+	for len(records) > 0 {
+		next := ""
+		for _, r := range records {
+			if r.Header.Type == q.Type {
+				resp.Answers = append(resp.Answers, r)
+			} else if cname, ok := r.Body.(*dnsmessage.CNAMEResource); ok {
+				resp.Answers = append(resp.Answers, r)
+				next = strings.ToLower(cname.CNAME.String())
+			}
+		}
+		records = testDNSRecords[next]
+	}
+	return resp
+}
+
+func TestDNSChecker(t *testing.T) {
+	resolver := startTestDNSServer(t)
+
+	tests := []struct {
+		name       string
+		host       string
+		recordType string
+		expected   []string
+		wantState  domain.State
+		wantClass  domain.ErrorClass
+	}{
+		{"a records", "app.test", domain.DNSRecordA, []string{"192.0.2.2"}, domain.StateUp, ""},
+		{"default record type is a", "app.test", "", nil, domain.StateUp, ""},
+		{"missing a record", "app.test", domain.DNSRecordA, []string{"192.0.2.9"}, domain.StateDown, domain.ErrorClassAssertion},
+		{"aaaa record in canonical form", "v6.test", domain.DNSRecordAAAA, []string{"2001:0db8::0001"}, domain.StateUp, ""},
+		{"a records through cname", "alias.test", domain.DNSRecordA, []string{"192.0.2.1"}, domain.StateUp, ""},
+		{"cname record", "alias.test", domain.DNSRecordCNAME, []string{"APP.test."}, domain.StateUp, ""},
+		{"host without cname record", "app.test", domain.DNSRecordCNAME, nil, domain.StateDown, domain.ErrorClassDNS},
+		{"mx host is case-insensitive", "mail.test", domain.DNSRecordMX, []string{"mx1.mail.test"}, domain.StateUp, ""},
+		{"txt value", "txt.test", domain.DNSRecordTXT, []string{"verify=AbC123"}, domain.StateUp, ""},
+		{"txt value is case-sensitive", "txt.test", domain.DNSRecordTXT, []string{"verify=abc123"}, domain.StateDown, domain.ErrorClassAssertion},
+		{"unknown host", "missing.test", domain.DNSRecordA, nil, domain.StateDown, domain.ErrorClassDNS},
+	}
+
+	c := &dnsChecker{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			res := c.Check(ctx, &domain.EndpointInfo{
+				URL:           tt.host,
+				DNSRecordType: tt.recordType,
+				DNSResolver:   resolver,
+				DNSExpected:   tt.expected,
+			})
+			if res.State != tt.wantState || res.ErrorClass != tt.wantClass {
+				t.Fatalf("got state %s, class %q, error %q; want state %s, class %q",
+					res.State, res.ErrorClass, res.Error, tt.wantState, tt.wantClass)
+			}
+			if res.Duration <= 0 {
+				t.Errorf("resolution time is not measured")
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/wrtgvr/websites-monitor/internal/domain"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Calls `grpc.health.v1.Health/Check` of `host:port` endpoint address.
+// Endpoint headers are sent as request metadata
+type grpcChecker struct{}
+
+func (c *grpcChecker) Check(ctx context.Context, ep *domain.EndpointInfo) *domain.CheckResult {
+	addr := strings.TrimPrefix(ep.URL, "grpc://")
+
+	host, _, _ := net.SplitHostPort(addr)
+	ci := newCertInspector(host)
+	creds := insecure.NewCredentials()
+	if ep.GRPCTLS {
+		creds = credentials.NewTLS(ci.tlsConfig(host))
+	}
+
+	start := time.Now()
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		res := failedResult(domain.ErrorClassConnect, err)
+		res.Duration = time.Since(start)
+		return res
+	}
+	defer conn.Close()
+
+	for _, h := range ep.Headers {
+		ctx = metadata.AppendToOutgoingContext(ctx, strings.ToLower(h.Name), h.Value)
+	}
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{
+		Service: ep.GRPCService,
+	})
+	if err != nil {
+		res := failedResult(grpcErrorClass(err), err)
+		res.Duration = time.Since(start)
+		ci.apply(res, ep.TLSExpiryThreshold())
+		return res
+	}
+
+	res := &domain.CheckResult{
+		State:    domain.StateUp,
+		Duration: time.Since(start),
+	}
+	switch resp.GetStatus() {
+	case healthpb.HealthCheckResponse_SERVING:
+	case healthpb.HealthCheckResponse_UNKNOWN:
+		res.State = domain.StateUnknown
+		res.ErrorClass = domain.ErrorClassStatusCode
+		res.Error = "health status UNKNOWN"
+	default:
+		res.State = domain.StateDown
+		res.ErrorClass = domain.ErrorClassStatusCode
+		res.Error = fmt.Sprintf("health status %s", resp.GetStatus())
+	}
+	ci.apply(res, ep.TLSExpiryThreshold())
+	return res
+}
+
+// Error class of failed health call
+func grpcErrorClass(err error) domain.ErrorClass {
+	st, ok := status.FromError(err)
+	if !ok {
+		return classifyError(err)
+	}
+
+	switch st.Code() {
+	case codes.DeadlineExceeded:
+		return domain.ErrorClassTimeout
+	case codes.Unavailable:
+		// grpc keeps only message of transport error
+		msg := st.Message()
+		switch {
+		case strings.Contains(msg, "tls:") || strings.Contains(msg, "x509:"):
+			return domain.ErrorClassTLS
+		case strings.Contains(msg, "name resolver error") || strings.Contains(msg, "no such host"):
+			return domain.ErrorClassDNS
+		case strings.Contains(msg, "connection error"):
+			return domain.ErrorClassConnect
+		default:
+			return domain.ErrorClassRead
+		}
+	default:
+		// server responded: NotFound for unknown service, Unimplemented without health service
+		return domain.ErrorClassStatusCode
+	}
+}
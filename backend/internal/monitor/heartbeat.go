@@ -0,0 +1,144 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/wrtgvr/websites-monitor/internal/domain"
+	errs "github.com/wrtgvr/websites-monitor/internal/errors"
+)
+
+// Record missed heartbeat if it's overdue.
+// Missed heartbeat is recorded once per period and grace until next signal.
+// Serialized with received signals of endpoint, so they are not lost or counted as misses
+func (s *Scheduler) checkHeartbeat(ctx context.Context, projectId string, ep *domain.EndpointInfo) {
+	unlock := s.heartbeatLocks.Lock(ep.ID)
+	defer unlock()
+
+	state, appErr := s.storage.GetHeartbeatState(ctx, projectId, ep.ID)
+	if appErr != nil {
+		log.Printf("ERR: failed to get heartbeat state, project_id=%s, endpoint_id=%s, err=%v\n", projectId, ep.ID, appErr)
+		return
+	}
+
+	now := time.Now()
+	if !state.DueAt.IsZero() && now.Before(state.DueAt) {
+		return
+	}
+
+	// zero due time means state is lost, then waiting starts from now
+	missed := !state.DueAt.IsZero()
+	state.DueAt = ep.HeartbeatDueAt(now)
+	if appErr := s.storage.UpdateHeartbeatState(ctx, projectId, ep.ID, state); appErr != nil {
+		log.Printf("ERR: failed to save heartbeat state, project_id=%s, endpoint_id=%s, err=%v\n", projectId, ep.ID, appErr)
+		return
+	}
+	if !missed {
+		return
+	}
+
+	s.record(ctx, projectId, ep, &domain.EndpointCheck{
+		EndpointID: ep.ID,
+		CheckedAt:  now,
+		CheckResult: domain.CheckResult{
+			State:      domain.StateDown,
+			ErrorClass: domain.ErrorClassHeartbeat,
+			Error:      "heartbeat was not received in time",
+		},
+	})
+}
+
+// Handle signal sent to heartbeat url with `token`.
+// Success and failure signals are recorded as checks with duration of job run
+func (s *Scheduler) ReceiveHeartbeat(ctx context.Context, token string, signal domain.HeartbeatSignal, message string) *errs.AppError {
+	ep, appErr := s.storage.GetEndpointByHeartbeatToken(ctx, token)
+	if appErr != nil {
+		return appErr
+	}
+	if ep.CheckType() != domain.CheckTypeHeartbeat {
+		return errs.NewNotFound(nil, "heartbeat not found")
+	}
+
+	unlock := s.heartbeatLocks.Lock(ep.ID)
+	defer unlock()
+
+	state, appErr := s.storage.GetHeartbeatState(ctx, ep.ProjectId, ep.ID)
+	if appErr != nil {
+		return appErr
+	}
+
+	now := time.Now()
+	if signal == domain.HeartbeatStart {
+		state.StartedAt = now
+		return s.storage.UpdateHeartbeatState(ctx, ep.ProjectId, ep.ID, state)
+	}
+
+	check := &domain.EndpointCheck{
+		EndpointID: ep.ID,
+		CheckedAt:  now,
+		CheckResult: domain.CheckResult{
+			State: domain.StateUp,
+		},
+	}
+	if !state.StartedAt.IsZero() {
+		check.Duration = now.Sub(state.StartedAt)
+	}
+	if signal == domain.HeartbeatFail {
+		check.State = domain.StateDown
+		check.ErrorClass = domain.ErrorClassHeartbeat
+		check.Error = "job reported failure"
+		if message != "" {
+			check.Error = fmt.Sprintf("job reported failure: %s", message)
+		}
+	}
+
+	state = &domain.HeartbeatState{
+		DueAt: ep.HeartbeatDueAt(now),
+	}
+	if appErr := s.storage.UpdateHeartbeatState(ctx, ep.ProjectId, ep.ID, state); appErr != nil {
+		return appErr
+	}
+
+	s.record(ctx, ep.ProjectId, ep, check)
+	return nil
+}
+
+// Mutex per key, entries are removed when nobody holds or waits for them
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*keyedLock
+}
+
+type keyedLock struct {
+	sync.Mutex
+	refs int
+}
+
+// Lock `key`, returns function that unlocks it
+func (k *keyedMutex) Lock(key string) func() {
+	k.mu.Lock()
+	if k.locks == nil {
+		k.locks = make(map[string]*keyedLock)
+	}
+	l, ok := k.locks[key]
+	if !ok {
+		l = &keyedLock{}
+		k.locks[key] = l
+	}
+	l.refs++
+	k.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+		k.mu.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(k.locks, key)
+		}
+		k.mu.Unlock()
+	}
+}
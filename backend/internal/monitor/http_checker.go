@@ -0,0 +1,114 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/wrtgvr/websites-monitor/internal/domain"
+)
+
+// Max amount of response body bytes read during check
+const maxBodySize = 1 << 20
+
+type httpChecker struct{}
+
+func (c *httpChecker) Check(ctx context.Context, ep *domain.EndpointInfo) *domain.CheckResult {
+	// fresh transport, so every check does tls handshake and records certificate
+	var host string
+	if u, err := url.Parse(ep.URL); err == nil {
+		host = u.Hostname()
+	}
+	ci := newCertInspector(host)
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = ci.tlsConfig("")
+	transport.DisableKeepAlives = true
+	defer transport.CloseIdleConnections()
+
+	client := &http.Client{
+		Transport: transport,
+	}
+	if ep.RedirectPolicy == domain.RedirectNone {
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+
+	//* build request
+	method := ep.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	var reqBody io.Reader
+	if ep.Body != "" {
+		reqBody = strings.NewReader(ep.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, ep.URL, reqBody)
+	if err != nil {
+		return failedResult(domain.ErrorClassConnect, err)
+	}
+	for _, h := range ep.Headers {
+		if strings.EqualFold(h.Name, "Host") {
+			req.Host = h.Value
+			continue
+		}
+		req.Header.Add(h.Name, h.Value)
+	}
+
+	start := time.Now()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		res := failedResult(classifyError(err), err)
+		res.Duration = time.Since(start)
+		ci.apply(res, ep.TLSExpiryThreshold())
+		return res
+	}
+	defer resp.Body.Close()
+
+	// read body so response time includes its transfer, extra byte shows that body was cut
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize+1))
+	if err != nil {
+		class := domain.ErrorClassRead
+		if c := classifyError(err); c == domain.ErrorClassTimeout {
+			class = c
+		}
+		res := failedResult(class, err)
+		res.StatusCode = resp.StatusCode
+		res.Duration = time.Since(start)
+		ci.apply(res, ep.TLSExpiryThreshold())
+		return res
+	}
+
+	truncated := len(body) > maxBodySize
+	if truncated {
+		body = body[:maxBodySize]
+	}
+
+	res := &domain.CheckResult{
+		State:      domain.StateUp,
+		StatusCode: resp.StatusCode,
+		Duration:   time.Since(start),
+	}
+	defer ci.apply(res, ep.TLSExpiryThreshold())
+
+	if !ep.ExpectedStatuses.Contains(resp.StatusCode) {
+		res.State = domain.StateDown
+		res.ErrorClass = domain.ErrorClassStatusCode
+		res.Error = fmt.Sprintf("unexpected status code %d", resp.StatusCode)
+		return res
+	}
+
+	if err := checkAssertions(ep.Assertions, resp.Header, body, truncated); err != nil {
+		res.State = domain.StateDown
+		res.ErrorClass = domain.ErrorClassAssertion
+		res.Error = err.Error()
+		return res
+	}
+
+	return res
+}
@@ -0,0 +1,225 @@
+package monitor
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/wrtgvr/websites-monitor/internal/domain"
+)
+
+const (
+	// Default size of subscriber channel buffer
+	defaultSubscriberBufferSize = 64
+	// Amount of last events kept per project for replay
+	replayBufferSize = 256
+)
+
+// What to do with a new status when subscriber buffer is full
+type DropPolicy string
+
+const (
+	// Drop the new status, keep buffered ones
+	DropNewest DropPolicy = "newest"
+	// Drop the oldest buffered status to make room for the new one
+	DropOldest DropPolicy = "oldest"
+	// Close subscription, client is expected to reconnect
+	DropSubscriber DropPolicy = "disconnect"
+)
+
+func (p DropPolicy) Valid() bool {
+	return p == DropNewest || p == DropOldest || p == DropSubscriber
+}
+
+// Endpoint status with hub-wide monotonically increasing id
+type Event struct {
+	ID     uint64
+	Status *domain.EndpointStatus
+}
+
+// Fan-out hub of endpoint statuses produced by scheduler.
+// Statuses are routed to subscribers of the status project only
+type Hub struct {
+	mu sync.Mutex
+	// id of last published event
+	lastId uint64
+	// project id -> subscriptions
+	subscribers map[string]map[*Subscription]struct{}
+	// project id -> last published events
+	replay map[string]*ring
+}
+
+type SubscribeOptions struct {
+	ProjectID string
+	// Id of last event client received. Newer buffered events are replayed before live ones.
+	// Zero means no replay
+	LastEventID uint64
+	// Endpoints to receive statuses of. Empty means every project endpoint
+	EndpointIDs []string
+	// Channel buffer size. Default is used if <= 0
+	BufferSize int
+	// Default is `DropNewest`
+	DropPolicy DropPolicy
+}
+
+type Subscription struct {
+	C         chan *Event
+	hub       *Hub
+	projectId string
+	// nil means every project endpoint
+	endpointIds map[string]struct{}
+	dropPolicy  DropPolicy
+	// guards sends to C and closing of C
+	mu      sync.Mutex
+	closed  bool
+	dropped atomic.Int64
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		// ids start from current time so they keep increasing across restarts
+		lastId:      uint64(time.Now().UnixNano()),
+		subscribers: make(map[string]map[*Subscription]struct{}),
+		replay:      make(map[string]*ring),
+	}
+}
+
+// Register new subscriber. Caller must call `Subscription.Close()` when done
+func (h *Hub) Subscribe(opts SubscribeOptions) *Subscription {
+	bufSize := opts.BufferSize
+	if bufSize <= 0 {
+		bufSize = defaultSubscriberBufferSize
+	}
+	policy := opts.DropPolicy
+	if !policy.Valid() {
+		policy = DropNewest
+	}
+
+	sub := &Subscription{
+		hub:        h,
+		projectId:  opts.ProjectID,
+		dropPolicy: policy,
+	}
+	if len(opts.EndpointIDs) > 0 {
+		sub.endpointIds = make(map[string]struct{}, len(opts.EndpointIDs))
+		for _, id := range opts.EndpointIDs {
+			sub.endpointIds[id] = struct{}{}
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	//* collect missed events
+	var missed []*Event
+	if opts.LastEventID > 0 && h.replay[sub.projectId] != nil {
+		for _, e := range h.replay[sub.projectId].since(opts.LastEventID) {
+			if sub.wants(e.Status) {
+				missed = append(missed, e)
+			}
+		}
+	}
+
+	// missed events must fit in buffer along with live ones
+	sub.C = make(chan *Event, bufSize+len(missed))
+	for _, e := range missed {
+		sub.C <- e
+	}
+
+	if h.subscribers[sub.projectId] == nil {
+		h.subscribers[sub.projectId] = make(map[*Subscription]struct{})
+	}
+	h.subscribers[sub.projectId][sub] = struct{}{}
+
+	return sub
+}
+
+// Send status to every matching subscriber and save it for replay.
+// Never blocks: full subscriber buffers are handled by subscriber drop policy
+func (h *Hub) Publish(status *domain.EndpointStatus) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastId++
+	e := &Event{
+		ID:     h.lastId,
+		Status: status,
+	}
+
+	if h.replay[status.ProjectId] == nil {
+		h.replay[status.ProjectId] = newRing(replayBufferSize)
+	}
+	h.replay[status.ProjectId].push(e)
+
+	for sub := range h.subscribers[status.ProjectId] {
+		if !sub.wants(status) {
+			continue
+		}
+		sub.deliver(e)
+	}
+}
+
+// Amount of statuses dropped for this subscriber
+func (s *Subscription) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+// Unregister subscription and close its channel
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	if subs, ok := s.hub.subscribers[s.projectId]; ok {
+		delete(subs, s)
+		if len(subs) == 0 {
+			delete(s.hub.subscribers, s.projectId)
+		}
+	}
+	s.hub.mu.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.C)
+	}
+}
+
+func (s *Subscription) wants(status *domain.EndpointStatus) bool {
+	if s.endpointIds == nil {
+		return true
+	}
+	_, ok := s.endpointIds[status.ID]
+	return ok
+}
+
+func (s *Subscription) deliver(e *Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	select {
+	case s.C <- e:
+		return
+	default:
+	}
+
+	//* buffer is full
+	s.dropped.Add(1)
+	switch s.dropPolicy {
+	case DropOldest:
+		select {
+		case <-s.C:
+		default:
+		}
+		select {
+		case s.C <- e:
+		default:
+		}
+	case DropSubscriber:
+		// subscription stays registered in hub until `Close()`, but gets nothing
+		s.closed = true
+		close(s.C)
+	}
+}
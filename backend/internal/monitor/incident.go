@@ -0,0 +1,135 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/wrtgvr/websites-monitor/internal/domain"
+	errs "github.com/wrtgvr/websites-monitor/internal/errors"
+)
+
+// Open incident when endpoint goes down and resolve it when endpoint recovers.
+// Checks that change check state are added to timeline of active incident.
+// `changed` is whether stable state changed, so incidents follow the same changes that are notified.
+// Updates incident of status, returns id of incident check belongs to, empty if there is none
+func (s *Scheduler) trackIncident(ctx context.Context, projectId string, ep *domain.EndpointInfo, status *domain.EndpointStatus, check *domain.EndpointCheck, prevCheckState domain.State, changed bool) string {
+	switch {
+	case changed && status.State == domain.StateDown && !s.incidentActive(ctx, projectId, status.IncidentID):
+		status.IncidentID = s.openIncident(ctx, projectId, ep, check)
+		return status.IncidentID
+	case changed && status.Up() && status.IncidentID != "":
+		id := status.IncidentID
+		s.resolveIncident(ctx, projectId, id, check)
+		status.IncidentID = ""
+		return id
+	case status.IncidentID != "" && check.State != prevCheckState:
+		s.addTimelineEntry(ctx, projectId, status.IncidentID, checkTimelineEntry(check))
+	}
+	return status.IncidentID
+}
+
+// Whether incident exists and is not resolved. Incident that could not be loaded counts as active
+func (s *Scheduler) incidentActive(ctx context.Context, projectId, incidentId string) bool {
+	if incidentId == "" {
+		return false
+	}
+	inc, appErr := s.storage.GetIncident(ctx, projectId, incidentId)
+	if appErr != nil {
+		if appErr.Type == errs.TypeNotFound {
+			return false
+		}
+		log.Printf("ERR: failed to get incident, project_id=%s, incident_id=%s, err=%v\n", projectId, incidentId, appErr)
+		return true
+	}
+	return inc.Active()
+}
+
+// Returns id of opened incident, empty if it could not be saved
+func (s *Scheduler) openIncident(ctx context.Context, projectId string, ep *domain.EndpointInfo, check *domain.EndpointCheck) string {
+	inc := &domain.Incident{
+		ID:           uuid.NewString(),
+		ProjectId:    projectId,
+		EndpointID:   ep.ID,
+		EndpointName: ep.Name,
+		State:        domain.IncidentOpen,
+		Cause:        check.Reason(),
+		OpenedAt:     check.CheckedAt,
+	}
+	timeline := []*domain.TimelineEntry{
+		checkTimelineEntry(check),
+		{
+			At:      check.CheckedAt,
+			Type:    domain.TimelineState,
+			State:   string(domain.IncidentOpen),
+			Message: "endpoint is down",
+		},
+	}
+	if appErr := s.storage.CreateIncident(ctx, inc, timeline); appErr != nil {
+		log.Printf("ERR: failed to open incident, project_id=%s, endpoint_id=%s, err=%v\n", projectId, ep.ID, appErr)
+		return ""
+	}
+	s.startEscalation(ctx, ep, inc)
+	return inc.ID
+}
+
+// Schedule first escalation step of opened incident if endpoint has escalation policy
+func (s *Scheduler) startEscalation(ctx context.Context, ep *domain.EndpointInfo, inc *domain.Incident) {
+	if ep.EscalationPolicyID == "" {
+		return
+	}
+	policy, appErr := s.storage.GetEscalationPolicy(ctx, inc.ProjectId, ep.EscalationPolicyID)
+	if appErr != nil {
+		if appErr.Type != errs.TypeNotFound {
+			log.Printf("ERR: failed to get escalation policy, project_id=%s, policy_id=%s, err=%v\n", inc.ProjectId, ep.EscalationPolicyID, appErr)
+		}
+		return
+	}
+	if len(policy.Steps) == 0 {
+		return
+	}
+	if appErr := s.storage.ScheduleEscalation(ctx, policy.Start(inc.ID, inc.OpenedAt)); appErr != nil {
+		log.Printf("ERR: failed to schedule escalation, project_id=%s, incident_id=%s, err=%v\n", inc.ProjectId, inc.ID, appErr)
+	}
+}
+
+// Resolve incident automatically. Incident resolved manually before is left as is
+func (s *Scheduler) resolveIncident(ctx context.Context, projectId, incidentId string, check *domain.EndpointCheck) {
+	_, appErr := s.storage.UpdateIncident(ctx, projectId, incidentId, func(inc *domain.Incident) error {
+		return inc.Resolve(check.CheckedAt, "")
+	})
+	if appErr != nil {
+		if appErr.Type == errs.TypeInternal {
+			log.Printf("ERR: failed to resolve incident, project_id=%s, incident_id=%s, err=%v\n", projectId, incidentId, appErr)
+		}
+		return
+	}
+
+	s.addTimelineEntry(ctx, projectId, incidentId, checkTimelineEntry(check))
+	s.addTimelineEntry(ctx, projectId, incidentId, &domain.TimelineEntry{
+		At:      check.CheckedAt,
+		Type:    domain.TimelineState,
+		State:   string(domain.IncidentResolved),
+		Message: "endpoint recovered",
+	})
+}
+
+func (s *Scheduler) addTimelineEntry(ctx context.Context, projectId, incidentId string, entry *domain.TimelineEntry) {
+	if appErr := s.storage.AddIncidentTimelineEntry(ctx, projectId, incidentId, entry); appErr != nil && appErr.Type != errs.TypeNotFound {
+		log.Printf("ERR: failed to add incident timeline entry, project_id=%s, incident_id=%s, err=%v\n", projectId, incidentId, appErr)
+	}
+}
+
+func checkTimelineEntry(check *domain.EndpointCheck) *domain.TimelineEntry {
+	msg := check.Reason()
+	if msg == "" && check.StatusCode != 0 {
+		msg = fmt.Sprintf("status code %d", check.StatusCode)
+	}
+	return &domain.TimelineEntry{
+		At:      check.CheckedAt,
+		Type:    domain.TimelineCheck,
+		State:   string(check.State),
+		Message: msg,
+	}
+}
@@ -1,75 +1,354 @@
 package monitor
 
 import (
+	"container/heap"
 	"context"
 	"log"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/wrtgvr/websites-monitor/internal/config"
 	"github.com/wrtgvr/websites-monitor/internal/domain"
+	errs "github.com/wrtgvr/websites-monitor/internal/errors"
+	"github.com/wrtgvr/websites-monitor/internal/notify"
 	"github.com/wrtgvr/websites-monitor/internal/storage"
 )
 
-var Config *config.MonitorConfig
+// Delay between attempts of failed check
+const retryDelay = time.Second
 
-type Monitor struct {
-	Out             chan *domain.EndpointStatus
-	storage         storage.EndpointsStorage
-	interval        time.Duration
-	pingTimeout     time.Duration
-	gorutinesAmount int
-	ticker          *time.Ticker
+// Long-lived scheduler that pings every endpoint of every project on its own interval,
+// saves results to storage, publishes them to hub and notifies channels about state changes.
+// Checks are spread over interval with deterministic per-endpoint offset
+// and run by fixed pool of workers
+type Scheduler struct {
+	storage  storage.Storage
+	hub      *Hub
+	notifier *notify.Notifier
+	// defaults for endpoints without own schedule
+	interval    time.Duration
+	pingTimeout time.Duration
+	workers     int
+	// interval between endpoint list reloads
+	refreshInterval time.Duration
+	// scheduled endpoints by id and their queue, accessed only by `Run()` goroutine
+	entries map[string]*entry
+	queue   checkQueue
+	// due checks passed to workers
+	jobs chan job
+	// fast re-checks requested by workers
+	rechecks chan recheck
+	stats    schedulerStats
+	wg       sync.WaitGroup
+	// serializes miss checks and received signals of heartbeat endpoints
+	heartbeatLocks keyedMutex
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
 }
 
-func NewMonitor(storage storage.EndpointsStorage) *Monitor {
-	if Config == nil {
-		log.Println("WARN: monitor config is nil")
-		return nil
-	}
-	return &Monitor{
+// Check passed to worker
+type job struct {
+	entry *entry
+	// endpoint config at dispatch time, entry config can be replaced during check
+	ep *domain.EndpointInfo
+}
+
+// Request to check endpoint earlier than its next slot
+type recheck struct {
+	entry *entry
+	at    time.Time
+}
+
+type schedulerStats struct {
+	endpoints atomic.Int64
+	running   atomic.Int64
+	checks    atomic.Int64
+	skipped   atomic.Int64
+}
+
+// Snapshot of scheduler counters
+type SchedulerStats struct {
+	// Scheduled endpoints
+	Endpoints int64
+	// Checks in progress
+	Running int64
+	// Checks finished since start
+	Checks int64
+	// Checks skipped since start because previous check of endpoint was still running
+	Skipped int64
+}
+
+func NewScheduler(storage storage.Storage, hub *Hub, notifier *notify.Notifier, cfg *config.MonitorConfig) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
 		storage:         storage,
-		interval:        Config.Interval,
-		pingTimeout:     Config.PingTimeout,
-		gorutinesAmount: Config.GorutinesAmount,
+		hub:             hub,
+		notifier:        notifier,
+		interval:        cfg.Interval,
+		pingTimeout:     cfg.PingTimeout,
+		workers:         cfg.GorutinesAmount,
+		refreshInterval: cfg.RefreshInterval,
+		entries:         make(map[string]*entry),
+		jobs:            make(chan job),
+		rechecks:        make(chan recheck),
+		ctx:             ctx,
+		cancel:          cancel,
+		done:            make(chan struct{}),
+	}
+}
+
+// Run scheduler until `Stop()` is called. Blocks
+func (s *Scheduler) Run() {
+	defer close(s.done)
+	// running checks must finish before scheduler is done
+	defer s.wg.Wait()
+	ctx := s.ctx
+
+	//* workers
+	for range s.workers {
+		s.wg.Add(1)
+		go s.runWorker(ctx)
+	}
+
+	//* dispatch due checks, reload endpoints every refresh tick
+	refreshTicker := time.NewTicker(s.refreshInterval)
+	defer refreshTicker.Stop()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	s.refresh(ctx, true)
+	for {
+		s.dispatchDue(ctx)
+
+		// sleep until next check
+		if e := s.queue.peek(); e != nil {
+			timer.Reset(time.Until(e.next))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-refreshTicker.C:
+			s.refresh(ctx, false)
+		case r := <-s.rechecks:
+			s.applyRecheck(r)
+		case <-timer.C:
+		}
 	}
 }
 
-func (m *Monitor) Run() {
-	//* ticker nad out chan
-	ticker := time.NewTicker(m.interval)
-	m.ticker = ticker
+// Stop scheduler and wait for running checks to finish.
+// Must be called only after `Run()` was started
+func (s *Scheduler) Stop() {
+	s.cancel()
+	<-s.done
+}
 
-	m.Out = make(chan *domain.EndpointStatus)
+// Current scheduler counters
+func (s *Scheduler) Stats() SchedulerStats {
+	return SchedulerStats{
+		Endpoints: s.stats.endpoints.Load(),
+		Running:   s.stats.running.Load(),
+		Checks:    s.stats.checks.Load(),
+		Skipped:   s.stats.skipped.Load(),
+	}
+}
 
-	//* ping endpoints every tick
-	for range ticker.C {
-		endpoints, err := m.storage.GetEndpointsForMonitoring(context.Background())
+// Reload endpoints of all projects: schedule new endpoints,
+// apply changed configs to scheduled ones and unschedule deleted endpoints.
+// Endpoints loaded on startup get their first check in their slot,
+// endpoints created later are checked right away
+func (s *Scheduler) refresh(ctx context.Context, startup bool) {
+	projectIds, err := s.storage.GetProjectIDs(ctx)
+	if err != nil {
+		log.Printf("ERR: scheduler could not get projects, err=%v\n", err)
+		return
+	}
+
+	now := time.Now()
+	seen := make(map[string]struct{}, len(s.entries))
+	// endpoints are unscheduled only if every project was loaded
+	complete := true
+
+	for _, projectId := range projectIds {
+		endpoints, err := s.storage.GetEndpointsForMonitoring(ctx, projectId)
+		if ctx.Err() != nil {
+			// scheduler is stopping
+			return
+		}
 		if err != nil {
-			log.Println("ERR: monitor could not get endpoints")
+			log.Printf("ERR: scheduler could not get endpoints, project_id=%s, err=%v\n", projectId, err)
+			complete = false
+			continue
+		}
+
+		for _, ep := range endpoints {
+			ep.ProjectId = projectId
+			seen[ep.ID] = struct{}{}
+			interval := ep.CheckInterval(s.interval)
+
+			// scheduled endpoint
+			if e, ok := s.entries[ep.ID]; ok {
+				e.ep = ep
+				if e.interval != interval {
+					e.interval = interval
+					s.queue.reschedule(e, nextSlot(ep.ID, interval, now))
+				}
+				continue
+			}
+
+			// new endpoint
+			e := &entry{
+				ep:       ep,
+				interval: interval,
+				next:     now,
+			}
+			if startup {
+				e.next = nextSlot(ep.ID, interval, now)
+			}
+			s.entries[ep.ID] = e
+			heap.Push(&s.queue, e)
+		}
+	}
+
+	if complete {
+		for id, e := range s.entries {
+			if _, ok := seen[id]; !ok {
+				heap.Remove(&s.queue, e.index)
+				delete(s.entries, id)
+			}
+		}
+	}
+	s.stats.endpoints.Store(int64(len(s.entries)))
+}
+
+// Pass every due check to workers and move its endpoint to next slot.
+// Check is skipped if previous check of endpoint is still running
+func (s *Scheduler) dispatchDue(ctx context.Context) {
+	for {
+		e := s.queue.peek()
+		now := time.Now()
+		if e == nil || now.Before(e.next) {
+			return
+		}
+		s.queue.reschedule(e, nextSlot(e.ep.ID, e.interval, now))
+
+		if !e.running.CompareAndSwap(false, true) {
+			s.stats.skipped.Add(1)
+			log.Printf("WARN: check skipped, previous one is still running, project_id=%s, endpoint_id=%s\n", e.ep.ProjectId, e.ep.ID)
 			continue
 		}
 
-		//* semaphore
-		sem := semaphore{
-			C: make(chan struct{}, m.gorutinesAmount),
+		// workers waiting to request re-check must not block dispatch
+		for sent := false; !sent; {
+			select {
+			case s.jobs <- job{entry: e, ep: e.ep}:
+				sent = true
+			case r := <-s.rechecks:
+				s.applyRecheck(r)
+			case <-ctx.Done():
+				return
+			}
 		}
+	}
+}
+
+// Move endpoint check to re-check time if it's earlier than next slot
+func (s *Scheduler) applyRecheck(r recheck) {
+	// endpoint was unscheduled during check
+	if r.entry.index < 0 {
+		return
+	}
+	if r.at.Before(r.entry.next) {
+		s.queue.reschedule(r.entry, r.at)
+	}
+}
+
+// Run checks passed by dispatcher until `ctx` is done
+func (s *Scheduler) runWorker(ctx context.Context) {
+	defer s.wg.Done()
 
-		//* ping endpoints
-		for _, v := range endpoints {
-			go func(ep *domain.EndpointInfo) {
-				defer recover()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case j := <-s.jobs:
+			s.stats.running.Add(1)
+			status := s.check(ctx, j.ep.ProjectId, j.ep)
+			s.stats.running.Add(-1)
+			s.stats.checks.Add(1)
+			j.entry.running.Store(false)
 
-				sem.acquire()
-				defer sem.release()
+			// state is not confirmed yet, check again sooner
+			if status != nil && j.ep.RecheckInterval > 0 && status.Confirming(status.State) {
+				select {
+				case s.rechecks <- recheck{entry: j.entry, at: time.Now().Add(j.ep.RecheckInterval)}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}
+}
 
-				//* ping endpoint and send result to monitor output channel
-				m.Out <- endpointPing(ep, m.pingTimeout)
-			}(v)
+// Ping endpoint and record result. Returns new endpoint status, nil if nothing was recorded
+func (s *Scheduler) check(ctx context.Context, projectId string, ep *domain.EndpointInfo) *domain.EndpointStatus {
+	if ep.CheckType() == domain.CheckTypeHeartbeat {
+		s.checkHeartbeat(ctx, projectId, ep)
+		return nil
+	}
+
+	//* ping, failed check is retried before endpoint is declared down
+	timeout := ep.CheckTimeout(s.pingTimeout)
+	check := endpointPing(ep, timeout)
+	for i := 0; i < ep.Retries && !check.Up(); i++ {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(retryDelay):
 		}
+		check = endpointPing(ep, timeout)
 	}
+
+	return s.record(ctx, projectId, ep, check)
 }
 
-func (m *Monitor) Stop() {
-	m.ticker.Stop()
-	close(m.Out)
+// Apply check result to endpoint status, track endpoint incident, save status and history record,
+// publish status to hub and notify channels if stable state changed.
+// Returns new endpoint status, nil if previous status could not be loaded
+func (s *Scheduler) record(ctx context.Context, projectId string, ep *domain.EndpointInfo, check *domain.EndpointCheck) *domain.EndpointStatus {
+	// finished check is saved even if scheduler is stopping
+	ctx = context.WithoutCancel(ctx)
+
+	status, appErr := s.storage.GetEndpointStatus(ctx, projectId, ep.ID)
+	if appErr != nil {
+		// endpoint was deleted during check
+		if appErr.Type != errs.TypeNotFound {
+			log.Printf("ERR: failed to get endpoint status, project_id=%s, endpoint_id=%s, err=%v\n", projectId, ep.ID, appErr)
+		}
+		return nil
+	}
+	prevSince, prevCheckState := status.StableSince, status.CheckState
+	prev, changed := status.Apply(ep, check)
+	incidentId := s.trackIncident(ctx, projectId, ep, status, check, prevCheckState, changed)
+
+	if err := s.storage.UpdateEndpointStatus(ctx, projectId, status); err != nil {
+		log.Printf("ERR: failed to save endpoint status, project_id=%s, endpoint_id=%s, err=%v\n", projectId, check.EndpointID, err)
+	}
+	if err := s.storage.AddEndpointCheck(ctx, projectId, check); err != nil {
+		log.Printf("ERR: failed to save endpoint check, project_id=%s, endpoint_id=%s, err=%v\n", projectId, check.EndpointID, err)
+	}
+
+	s.hub.Publish(status)
+	if changed {
+		if change := domain.NewStateChange(ep, status, prev, prevSince); change != nil {
+			change.IncidentID = incidentId
+			s.notifier.Notify(change)
+		}
+	}
+	return status
 }
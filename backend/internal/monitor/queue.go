@@ -0,0 +1,79 @@
+package monitor
+
+import (
+	"container/heap"
+	"hash/fnv"
+	"sync/atomic"
+	"time"
+
+	"github.com/wrtgvr/websites-monitor/internal/domain"
+)
+
+// Scheduled endpoint
+type entry struct {
+	// latest endpoint config, replaced on refresh
+	ep       *domain.EndpointInfo
+	interval time.Duration
+	// time of next check
+	next time.Time
+	// position in queue
+	index int
+	// previous check is not finished yet
+	running atomic.Bool
+}
+
+// Min-heap of entries by next check time
+type checkQueue []*entry
+
+func (q checkQueue) Len() int           { return len(q) }
+func (q checkQueue) Less(i, j int) bool { return q[i].next.Before(q[j].next) }
+func (q checkQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *checkQueue) Push(x any) {
+	e := x.(*entry)
+	e.index = len(*q)
+	*q = append(*q, e)
+}
+
+func (q *checkQueue) Pop() any {
+	old := *q
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	e.index = -1
+	*q = old[:len(old)-1]
+	return e
+}
+
+// Entry with the earliest next check, nil if queue is empty
+func (q checkQueue) peek() *entry {
+	if len(q) == 0 {
+		return nil
+	}
+	return q[0]
+}
+
+// Move entry to `next` check time
+func (q *checkQueue) reschedule(e *entry, next time.Time) {
+	e.next = next
+	heap.Fix(q, e.index)
+}
+
+// Deterministic offset of endpoint checks inside interval,
+// so checks of endpoints with the same interval are spread evenly
+func phase(endpointId string, interval time.Duration) time.Duration {
+	h := fnv.New64a()
+	h.Write([]byte(endpointId))
+	return time.Duration(h.Sum64() % uint64(interval))
+}
+
+// First check slot of endpoint after `after`.
+// Slots are at `phase + k*interval` since unix epoch
+func nextSlot(endpointId string, interval time.Duration, after time.Time) time.Time {
+	offset := int64(phase(endpointId, interval))
+	k := (after.UnixNano()-offset)/int64(interval) + 1
+	return time.Unix(0, k*int64(interval)+offset)
+}
@@ -0,0 +1,83 @@
+package monitor
+
+import (
+	"container/heap"
+	"testing"
+	"time"
+)
+
+func TestNextSlot(t *testing.T) {
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	// `base` is a multiple of every tested interval since epoch, so `base + phase` is endpoint slot
+	slotOf := func(id string, interval time.Duration) time.Time {
+		return base.Add(phase(id, interval))
+	}
+
+	tests := []struct {
+		name     string
+		id       string
+		interval time.Duration
+		// offsets of `after` and expected slot from endpoint slot
+		after time.Duration
+		want  time.Duration
+	}{
+		{"before slot", "ep-1", time.Minute, -time.Second, 0},
+		{"exactly at slot", "ep-1", time.Minute, 0, time.Minute},
+		{"right after slot", "ep-1", time.Minute, time.Nanosecond, time.Minute},
+		{"several intervals later", "ep-2", 30 * time.Second, 95 * time.Second, 120 * time.Second},
+		{"long interval", "ep-3", time.Hour, 59 * time.Minute, time.Hour},
+		{"sub-second interval", "ep-4", 250 * time.Millisecond, 300 * time.Millisecond, 500 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slot := slotOf(tt.id, tt.interval)
+			got := nextSlot(tt.id, tt.interval, slot.Add(tt.after))
+			if want := slot.Add(tt.want); !got.Equal(want) {
+				t.Fatalf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestNextSlotSpreadsEndpoints(t *testing.T) {
+	after := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	interval := time.Minute
+	seen := map[time.Time]bool{}
+	for _, id := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		next := nextSlot(id, interval, after)
+		if !next.After(after) || next.Sub(after) > interval {
+			t.Fatalf("slot %v of %s is not within interval after %v", next, id, after)
+		}
+		if next != nextSlot(id, interval, after) {
+			t.Fatalf("slot of %s is not deterministic", id)
+		}
+		seen[next] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("all endpoints got the same slot")
+	}
+}
+
+func TestCheckQueue(t *testing.T) {
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var q checkQueue
+	entries := map[string]*entry{}
+	for i, id := range []string{"c", "a", "b"} {
+		entries[id] = &entry{next: base.Add(time.Duration(len(id)+i) * time.Second)}
+		heap.Push(&q, entries[id])
+	}
+	if q.peek() != entries["c"] {
+		t.Fatal("queue does not start with the earliest entry")
+	}
+
+	q.reschedule(entries["c"], base.Add(time.Minute))
+	if q.peek() != entries["a"] {
+		t.Fatal("rescheduled entry is still first")
+	}
+
+	heap.Remove(&q, entries["a"].index)
+	if entries["a"].index != -1 || q.peek() != entries["b"] {
+		t.Fatalf("removed entry index %d, first entry is not the next earliest", entries["a"].index)
+	}
+}
@@ -0,0 +1,39 @@
+package monitor
+
+// Fixed-size buffer of last events, oldest are overwritten
+type ring struct {
+	events []*Event
+	// index of next write
+	next int
+	full bool
+}
+
+func newRing(size int) *ring {
+	return &ring{
+		events: make([]*Event, size),
+	}
+}
+
+func (r *ring) push(e *Event) {
+	r.events[r.next] = e
+	r.next = (r.next + 1) % len(r.events)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Events with id greater than `id`, oldest first
+func (r *ring) since(id uint64) []*Event {
+	var ordered []*Event
+	if r.full {
+		ordered = append(ordered, r.events[r.next:]...)
+	}
+	ordered = append(ordered, r.events[:r.next]...)
+
+	for i, e := range ordered {
+		if e.ID > id {
+			return ordered[i:]
+		}
+	}
+	return nil
+}
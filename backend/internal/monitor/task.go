@@ -1,42 +1,69 @@
 package monitor
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
-	"log"
-	"net/http"
+	"net"
 	"time"
 
 	"github.com/wrtgvr/websites-monitor/internal/domain"
 )
 
-func endpointPing(ep *domain.EndpointInfo, timeout time.Duration) *domain.EndpointStatus {
-	client := &http.Client{
-		Timeout: timeout,
+// Run check of endpoint type with timeout
+func endpointPing(ep *domain.EndpointInfo, timeout time.Duration) *domain.EndpointCheck {
+	check := &domain.EndpointCheck{
+		EndpointID: ep.ID,
+		CheckedAt:  time.Now(),
 	}
 
-	var status string
-	pingedAt := time.Now()
+	checker, ok := checkers[ep.CheckType()]
+	if !ok {
+		check.CheckResult = domain.CheckResult{
+			State: domain.StateUnknown,
+			Error: fmt.Sprintf("unknown check type: %s", ep.Type),
+		}
+		return check
+	}
 
-	resp, err := client.Get(ep.URL)
-	if err != nil {
-		status = "Error: check logs"
-		log.Printf("Error pinging, err=%v", err)
-	} else {
-		defer resp.Body.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
 
-		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-			status = fmt.Sprintf("Success: %d", resp.StatusCode)
-		} else {
-			status = fmt.Sprintf("Failure: %d", resp.StatusCode)
-		}
+	check.CheckResult = *checker.Check(ctx, ep)
+	return check
+}
+
+// Get class of error returned by dial or request
+func classifyError(err error) domain.ErrorClass {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return domain.ErrorClassDNS
 	}
 
-	respTime := time.Since(pingedAt)
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return domain.ErrorClassTimeout
+	}
+
+	var (
+		recordErr    tls.RecordHeaderError
+		verifyErr    *tls.CertificateVerificationError
+		alertErr     tls.AlertError
+		authorityErr x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+		invalidErr   x509.CertificateInvalidError
+	)
+	if errors.As(err, &recordErr) || errors.As(err, &verifyErr) || errors.As(err, &alertErr) ||
+		errors.As(err, &authorityErr) || errors.As(err, &hostnameErr) || errors.As(err, &invalidErr) {
+		return domain.ErrorClassTLS
+	}
 
-	return &domain.EndpointStatus{
-		ID:           ep.ID,
-		Status:       status,
-		LastChecked:  pingedAt.Format(time.RFC3339),
-		ResponseTime: pingedAt.Add(respTime).Format(time.RFC3339),
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return domain.ErrorClassConnect
 	}
+
+	return domain.ErrorClassRead
 }
@@ -0,0 +1,88 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/wrtgvr/websites-monitor/internal/domain"
+)
+
+// Connects to `host:port` endpoint address.
+// Optionally sends payload and expects response to start with banner
+type tcpChecker struct{}
+
+func (c *tcpChecker) Check(ctx context.Context, ep *domain.EndpointInfo) *domain.CheckResult {
+	addr := strings.TrimPrefix(ep.URL, "tcp://")
+
+	//* connect
+	var dialer net.Dialer
+	start := time.Now()
+
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		res := failedResult(classifyError(err), err)
+		res.Duration = time.Since(start)
+		return res
+	}
+	defer conn.Close()
+
+	res := &domain.CheckResult{
+		State:    domain.StateUp,
+		Duration: time.Since(start),
+	}
+
+	if ep.TCPPayload == "" && ep.TCPExpectBanner == "" {
+		return res
+	}
+
+	// connection must not outlive check
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	//* send payload
+	if ep.TCPPayload != "" {
+		if _, err := io.WriteString(conn, ep.TCPPayload); err != nil {
+			return c.ioFailure(res, fmt.Errorf("failed to send payload: %w", err))
+		}
+	}
+
+	//* read banner
+	if ep.TCPExpectBanner != "" {
+		banner := make([]byte, len(ep.TCPExpectBanner))
+		n, err := io.ReadFull(conn, banner)
+		if err != nil && !bytes.HasPrefix([]byte(ep.TCPExpectBanner), banner[:n]) {
+			return c.bannerMismatch(res, banner[:n])
+		}
+		if err != nil {
+			return c.ioFailure(res, fmt.Errorf("failed to read banner: %w", err))
+		}
+		if !bytes.Equal(banner, []byte(ep.TCPExpectBanner)) {
+			return c.bannerMismatch(res, banner)
+		}
+	}
+
+	return res
+}
+
+func (c *tcpChecker) ioFailure(res *domain.CheckResult, err error) *domain.CheckResult {
+	res.State = domain.StateDown
+	res.ErrorClass = domain.ErrorClassRead
+	if classifyError(err) == domain.ErrorClassTimeout {
+		res.ErrorClass = domain.ErrorClassTimeout
+	}
+	res.Error = err.Error()
+	return res
+}
+
+func (c *tcpChecker) bannerMismatch(res *domain.CheckResult, got []byte) *domain.CheckResult {
+	res.State = domain.StateDown
+	res.ErrorClass = domain.ErrorClassAssertion
+	res.Error = fmt.Sprintf("assertion failed: banner does not start with expected prefix, got %q", got)
+	return res
+}
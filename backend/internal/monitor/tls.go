@@ -0,0 +1,97 @@
+package monitor
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wrtgvr/websites-monitor/internal/domain"
+)
+
+// Records server certificate during handshake and verifies chain itself,
+// so certificate is recorded even if it's invalid.
+// Handshake may still run after check timed out, so recorded certificate is guarded by mutex
+type certInspector struct {
+	// Monitored host, certificates of other hosts (e.g. redirect targets) are not recorded
+	host string
+
+	mu   sync.Mutex
+	info *domain.CertInfo
+}
+
+func newCertInspector(host string) *certInspector {
+	return &certInspector{host: host}
+}
+
+// Client tls config for `serverName`. Empty server name is set by http transport per host
+func (ci *certInspector) tlsConfig(serverName string) *tls.Config {
+	return &tls.Config{
+		ServerName: serverName,
+		// chain is verified in `VerifyConnection`
+		InsecureSkipVerify: true,
+		VerifyConnection:   ci.verify,
+	}
+}
+
+func (ci *certInspector) verify(cs tls.ConnectionState) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("server did not present certificate")
+	}
+	leaf := cs.PeerCertificates[0]
+
+	info := &domain.CertInfo{
+		NotAfter: leaf.NotAfter,
+		Issuer:   leaf.Issuer.String(),
+		Subject:  leaf.Subject.String(),
+		SANs:     append([]string{}, leaf.DNSNames...),
+	}
+	for _, ip := range leaf.IPAddresses {
+		info.SANs = append(info.SANs, ip.String())
+	}
+
+	//* verify chain
+	intermediates := x509.NewCertPool()
+	for _, c := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(c)
+	}
+	_, err := leaf.Verify(x509.VerifyOptions{
+		DNSName:       cs.ServerName,
+		Intermediates: intermediates,
+	})
+	info.ChainValid = err == nil
+	if err != nil {
+		info.ChainError = err.Error()
+	}
+
+	// server name is empty for ip hosts, then only first handshake is recorded,
+	// it is always one with monitored host
+	ci.mu.Lock()
+	if strings.EqualFold(cs.ServerName, ci.host) || (cs.ServerName == "" && ci.info == nil && net.ParseIP(ci.host) != nil) {
+		ci.info = info
+	}
+	ci.mu.Unlock()
+	return err
+}
+
+// Attach recorded certificate to result.
+// Up result becomes degraded if certificate expires within threshold
+func (ci *certInspector) apply(res *domain.CheckResult, threshold time.Duration) {
+	ci.mu.Lock()
+	info := ci.info
+	ci.mu.Unlock()
+	if info == nil {
+		return
+	}
+	res.Cert = info
+
+	if res.State == domain.StateUp && info.ExpiresWithin(time.Now(), threshold) {
+		res.State = domain.StateDegraded
+		res.ErrorClass = domain.ErrorClassTLS
+		res.Error = fmt.Sprintf("certificate expires at %s", info.NotAfter.Format(time.RFC3339))
+	}
+}
@@ -0,0 +1,48 @@
+package monitor
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/wrtgvr/websites-monitor/internal/domain"
+)
+
+// Default port of tls check
+const defaultTLSPort = "443"
+
+// Performs tls handshake with `host[:port]` endpoint address and inspects server certificate
+type tlsChecker struct{}
+
+func (c *tlsChecker) Check(ctx context.Context, ep *domain.EndpointInfo) *domain.CheckResult {
+	addr := strings.TrimPrefix(ep.URL, "tls://")
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+		addr = net.JoinHostPort(addr, defaultTLSPort)
+	}
+
+	ci := newCertInspector(host)
+	dialer := &tls.Dialer{
+		Config: ci.tlsConfig(host),
+	}
+	start := time.Now()
+
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		res := failedResult(classifyError(err), err)
+		res.Duration = time.Since(start)
+		ci.apply(res, ep.TLSExpiryThreshold())
+		return res
+	}
+	conn.Close()
+
+	res := &domain.CheckResult{
+		State:    domain.StateUp,
+		Duration: time.Since(start),
+	}
+	ci.apply(res, ep.TLSExpiryThreshold())
+	return res
+}
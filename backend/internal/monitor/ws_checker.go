@@ -0,0 +1,117 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/wrtgvr/websites-monitor/internal/domain"
+)
+
+// Performs websocket handshake with endpoint url.
+// Optionally sends message and waits for reply matching pattern
+type wsChecker struct{}
+
+func (c *wsChecker) Check(ctx context.Context, ep *domain.EndpointInfo) *domain.CheckResult {
+	var host string
+	if u, err := url.Parse(ep.URL); err == nil {
+		host = u.Hostname()
+	}
+	ci := newCertInspector(host)
+	dialer := &websocket.Dialer{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: ci.tlsConfig(""),
+	}
+	header := http.Header{}
+	for _, h := range ep.Headers {
+		header.Add(h.Name, h.Value)
+	}
+
+	//* expect pattern is compiled on load
+	pattern := ep.WSPattern()
+	if pattern == nil && ep.WSExpectPattern != "" {
+		var err error
+		if pattern, err = regexp.Compile(ep.WSExpectPattern); err != nil {
+			return failedResult(domain.ErrorClassAssertion, fmt.Errorf("invalid expect pattern: %v", err))
+		}
+	}
+
+	//* handshake
+	start := time.Now()
+
+	conn, resp, err := dialer.DialContext(ctx, ep.URL, header)
+	if err != nil {
+		res := failedResult(classifyError(err), err)
+		if errors.Is(err, websocket.ErrBadHandshake) && resp != nil {
+			res = failedResult(domain.ErrorClassStatusCode, fmt.Errorf("unexpected handshake status code %d", resp.StatusCode))
+			res.StatusCode = resp.StatusCode
+		}
+		res.Duration = time.Since(start)
+		ci.apply(res, ep.TLSExpiryThreshold())
+		return res
+	}
+	defer conn.Close()
+
+	res := &domain.CheckResult{
+		State:      domain.StateUp,
+		StatusCode: resp.StatusCode,
+		Handshake:  time.Since(start),
+	}
+	res.Duration = res.Handshake
+	defer ci.apply(res, ep.TLSExpiryThreshold())
+
+	if ep.WSMessage == "" && ep.WSExpectPattern == "" {
+		conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+		return res
+	}
+
+	// connection must not outlive check
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetWriteDeadline(deadline)
+		conn.SetReadDeadline(deadline)
+	}
+
+	//* send message
+	sentAt := time.Now()
+	if ep.WSMessage != "" {
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(ep.WSMessage)); err != nil {
+			return c.failure(res, start, domain.ErrorClassRead, fmt.Errorf("failed to send message: %w", err))
+		}
+	}
+
+	//* wait for reply, messages not matching pattern are skipped
+	var last []byte
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			if last != nil && classifyError(err) == domain.ErrorClassTimeout {
+				return c.failure(res, start, domain.ErrorClassAssertion,
+					fmt.Errorf("assertion failed: no reply matched pattern %q, last reply %.64q", ep.WSExpectPattern, last))
+			}
+			return c.failure(res, start, classifyError(err), err)
+		}
+		if pattern == nil || pattern.Match(msg) {
+			break
+		}
+		last = msg
+	}
+
+	res.RoundTrip = time.Since(sentAt)
+	res.Duration = time.Since(start)
+	conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+	return res
+}
+
+// Mark result of established connection as failed
+func (c *wsChecker) failure(res *domain.CheckResult, start time.Time, class domain.ErrorClass, err error) *domain.CheckResult {
+	res.State = domain.StateDown
+	res.ErrorClass = class
+	res.Error = err.Error()
+	res.Duration = time.Since(start)
+	return res
+}
@@ -0,0 +1,241 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"html"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/wrtgvr/websites-monitor/internal/domain"
+)
+
+// Colors of state change
+const (
+	colorDown = 0xC62828
+	colorUp   = 0x2E7D32
+)
+
+// Posts message in native payload format of chat platform
+type chatSender struct {
+	client *http.Client
+	// Url payload is posted to
+	url func(ch *domain.NotificationChannel) string
+	// Platform payload, encoded to JSON
+	payload func(ch *domain.NotificationChannel, msg *message) any
+}
+
+func newChatSender(
+	url func(ch *domain.NotificationChannel) string,
+	payload func(ch *domain.NotificationChannel, msg *message) any,
+) *chatSender {
+	return &chatSender{
+		client:  newClient(),
+		url:     url,
+		payload: payload,
+	}
+}
+
+func (s *chatSender) Send(ctx context.Context, ch *domain.NotificationChannel, msg *message) (int, error) {
+	body, err := json.Marshal(s.payload(ch, msg))
+	if err != nil {
+		return 0, err
+	}
+	return postJSON(ctx, s.client, s.url(ch), body, nil)
+}
+
+func webhookURL(ch *domain.NotificationChannel) string {
+	return ch.WebhookURL
+}
+
+//* slack
+
+// Escape text for slack mrkdwn
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// Message with blocks, `text` is shown in notifications
+func slackPayload(ch *domain.NotificationChannel, msg *message) any {
+	sum := summarize(msg)
+
+	fields := []map[string]any{}
+	for _, f := range sum.facts() {
+		fields = append(fields, map[string]any{
+			"type": "mrkdwn",
+			// section field text is limited to 2000 characters
+			"text": truncate("*"+f.Name+"*\n"+slackEscape(f.Value), 2000),
+		})
+	}
+
+	emoji := ":red_circle:"
+	if sum.Up {
+		emoji = ":large_green_circle:"
+	}
+	blocks := []map[string]any{
+		{
+			"type": "header",
+			"text": map[string]any{
+				"type":  "plain_text",
+				"text":  truncate(sum.Title, 150),
+				"emoji": true,
+			},
+		},
+		{
+			"type":   "section",
+			"fields": fields,
+		},
+	}
+	if sum.Link != "" {
+		blocks = append(blocks, map[string]any{
+			"type": "actions",
+			"elements": []map[string]any{{
+				"type": "button",
+				"text": map[string]any{"type": "plain_text", "text": "View endpoint"},
+				"url":  sum.Link,
+			}},
+		})
+	}
+
+	return map[string]any{
+		"text":   emoji + " " + slackEscape(sum.Title),
+		"blocks": blocks,
+	}
+}
+
+//* discord
+
+type discordMessage struct {
+	Embeds []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title     string              `json:"title"`
+	URL       string              `json:"url,omitempty"`
+	Color     int                 `json:"color"`
+	Fields    []discordEmbedField `json:"fields"`
+	Timestamp string              `json:"timestamp"`
+}
+
+type discordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+// Message with single embed
+func discordPayload(ch *domain.NotificationChannel, msg *message) any {
+	sum := summarize(msg)
+
+	embed := discordEmbed{
+		Title:     truncate(sum.Title, 256),
+		URL:       sum.Link,
+		Color:     colorDown,
+		Timestamp: msg.At.UTC().Format(time.RFC3339),
+	}
+	if sum.Up {
+		embed.Color = colorUp
+	}
+	for _, f := range sum.facts() {
+		embed.Fields = append(embed.Fields, discordEmbedField{
+			Name:  f.Name,
+			Value: truncate(f.Value, 1024),
+			// long values take whole row
+			Inline: f.Name != "URL" && f.Name != "Reason",
+		})
+	}
+
+	return &discordMessage{Embeds: []discordEmbed{embed}}
+}
+
+//* teams
+
+// Message with adaptive card attachment
+func teamsPayload(ch *domain.NotificationChannel, msg *message) any {
+	sum := summarize(msg)
+
+	color := "Attention"
+	if sum.Up {
+		color = "Good"
+	}
+	facts := []map[string]any{}
+	for _, f := range sum.facts() {
+		facts = append(facts, map[string]any{"title": f.Name, "value": f.Value})
+	}
+
+	card := map[string]any{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body": []map[string]any{
+			{
+				"type":   "TextBlock",
+				"text":   sum.Title,
+				"size":   "Medium",
+				"weight": "Bolder",
+				"color":  color,
+				"wrap":   true,
+			},
+			{
+				"type":  "FactSet",
+				"facts": facts,
+			},
+		},
+	}
+	if sum.Link != "" {
+		card["actions"] = []map[string]any{{
+			"type":  "Action.OpenUrl",
+			"title": "View endpoint",
+			"url":   sum.Link,
+		}}
+	}
+
+	return map[string]any{
+		"type": "message",
+		"attachments": []map[string]any{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content":     card,
+		}},
+	}
+}
+
+//* telegram
+
+type telegramSendMessage struct {
+	ChatID                string `json:"chat_id"`
+	Text                  string `json:"text"`
+	ParseMode             string `json:"parse_mode"`
+	DisableWebPagePreview bool   `json:"disable_web_page_preview"`
+}
+
+// Bot API sendMessage method url
+func telegramURL(ch *domain.NotificationChannel) string {
+	return ch.TelegramAPI() + "/bot" + ch.TelegramBotToken + "/sendMessage"
+}
+
+// sendMessage request with html formatted text
+func telegramPayload(ch *domain.NotificationChannel, msg *message) any {
+	sum := summarize(msg)
+
+	emoji := "🔴"
+	if sum.Up {
+		emoji = "🟢"
+	}
+	var b strings.Builder
+	b.WriteString(emoji + " <b>" + html.EscapeString(sum.Title) + "</b>\n")
+	for _, f := range sum.facts() {
+		// message text is limited to 4096 characters
+		b.WriteString("\n<b>" + f.Name + ":</b> " + html.EscapeString(truncate(f.Value, 1024)))
+	}
+	if sum.Link != "" {
+		b.WriteString("\n\n<a href=\"" + html.EscapeString(sum.Link) + "\">View endpoint</a>")
+	}
+
+	return &telegramSendMessage{
+		ChatID:                ch.TelegramChatID,
+		Text:                  b.String(),
+		ParseMode:             "HTML",
+		DisableWebPagePreview: true,
+	}
+}
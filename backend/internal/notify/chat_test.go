@@ -0,0 +1,160 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/wrtgvr/websites-monitor/internal/domain"
+)
+
+// Decoded JSON value at path of object keys and array indexes, nil if there is none
+func jsonAt(v any, path ...any) any {
+	for _, p := range path {
+		switch key := p.(type) {
+		case string:
+			obj, ok := v.(map[string]any)
+			if !ok {
+				return nil
+			}
+			v = obj[key]
+		case int:
+			arr, ok := v.([]any)
+			if !ok || key >= len(arr) {
+				return nil
+			}
+			v = arr[key]
+		}
+	}
+	return v
+}
+
+func TestChatSenders(t *testing.T) {
+	tests := []struct {
+		chType   domain.ChannelType
+		wantPath string
+		// checks decoded payload
+		check func(t *testing.T, payload any)
+	}{
+		{domain.ChannelTypeSlack, "/services/T0/B0/x", func(t *testing.T, payload any) {
+			if got := jsonAt(payload, "text"); got != ":red_circle: api &lt;v2&gt; &amp; co is DOWN" {
+				t.Errorf("got text %q", got)
+			}
+			if got := jsonAt(payload, "blocks", 0, "text", "text"); got != "api <v2> & co is DOWN" {
+				t.Errorf("got header %q", got)
+			}
+			if got := jsonAt(payload, "blocks", 1, "fields", 0, "text"); got != "*Endpoint*\napi &lt;v2&gt; &amp; co" {
+				t.Errorf("got first field %q", got)
+			}
+			if got := jsonAt(payload, "blocks", 2, "elements", 0, "url"); got != "https://monitor.example.com/p/ep" {
+				t.Errorf("got button url %q", got)
+			}
+		}},
+		{domain.ChannelTypeDiscord, "/api/webhooks/1/x", func(t *testing.T, payload any) {
+			if got := jsonAt(payload, "embeds", 0, "title"); got != "api <v2> & co is DOWN" {
+				t.Errorf("got title %q", got)
+			}
+			if got := jsonAt(payload, "embeds", 0, "color"); got != float64(colorDown) {
+				t.Errorf("got color %v", got)
+			}
+			if got := jsonAt(payload, "embeds", 0, "timestamp"); got != "2024-01-01T12:00:00Z" {
+				t.Errorf("got timestamp %q", got)
+			}
+			if got := jsonAt(payload, "embeds", 0, "fields", 1, "name"); got != "URL" {
+				t.Errorf("got second field %q", got)
+			}
+			if got := jsonAt(payload, "embeds", 0, "fields", 1, "inline"); got != false {
+				t.Errorf("url field is inline")
+			}
+		}},
+		{domain.ChannelTypeTeams, "/webhook/x", func(t *testing.T, payload any) {
+			card := jsonAt(payload, "attachments", 0)
+			if got := jsonAt(card, "contentType"); got != "application/vnd.microsoft.card.adaptive" {
+				t.Errorf("got content type %q", got)
+			}
+			if got := jsonAt(card, "content", "body", 0, "text"); got != "api <v2> & co is DOWN" {
+				t.Errorf("got title %q", got)
+			}
+			if got := jsonAt(card, "content", "body", 0, "color"); got != "Attention" {
+				t.Errorf("got color %q", got)
+			}
+			if got := jsonAt(card, "content", "body", 1, "facts", 2, "value"); got != "up → down" {
+				t.Errorf("got state fact %q", got)
+			}
+			if got := jsonAt(card, "content", "actions", 0, "url"); got != "https://monitor.example.com/p/ep" {
+				t.Errorf("got action url %q", got)
+			}
+		}},
+		{domain.ChannelTypeTelegram, "/bot123:abc/sendMessage", func(t *testing.T, payload any) {
+			if got := jsonAt(payload, "chat_id"); got != "@ops" {
+				t.Errorf("got chat id %q", got)
+			}
+			if got := jsonAt(payload, "parse_mode"); got != "HTML" {
+				t.Errorf("got parse mode %q", got)
+			}
+			text, _ := jsonAt(payload, "text").(string)
+			for _, want := range []string{
+				"🔴 <b>api &lt;v2&gt; &amp; co is DOWN</b>",
+				"<b>Reason:</b> status_code: unexpected status code 503",
+				`<a href="https://monitor.example.com/p/ep">View endpoint</a>`,
+			} {
+				if !strings.Contains(text, want) {
+					t.Errorf("text does not contain %q:\n%s", want, text)
+				}
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.chType), func(t *testing.T) {
+			srv, received := startReceiver(t, http.StatusOK)
+			ch := &domain.NotificationChannel{
+				Type:             tt.chType,
+				WebhookURL:       srv.URL + tt.wantPath,
+				TelegramAPIURL:   srv.URL,
+				TelegramBotToken: "123:abc",
+				TelegramChatID:   "@ops",
+			}
+			msg := testMessage()
+			msg.EndpointName = "api <v2> & co"
+
+			if code, err := senders[tt.chType].Send(context.Background(), ch, msg); err != nil || code != http.StatusOK {
+				t.Fatalf("got code %d, err %v", code, err)
+			}
+			req := <-received
+			if req.path != tt.wantPath {
+				t.Errorf("got path %q, want %q", req.path, tt.wantPath)
+			}
+			if got := req.header.Get("Content-Type"); got != "application/json" {
+				t.Errorf("got content type %q", got)
+			}
+			var payload any
+			if err := json.Unmarshal(req.body, &payload); err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, payload)
+		})
+	}
+}
+
+func TestChatSenderErrorHidesURL(t *testing.T) {
+	srv, _ := startReceiver(t, http.StatusOK)
+	// closed server refuses connection
+	srv.Close()
+	ch := &domain.NotificationChannel{
+		Type:             domain.ChannelTypeTelegram,
+		TelegramAPIURL:   srv.URL,
+		TelegramBotToken: "123:secret-token",
+		TelegramChatID:   "@ops",
+	}
+
+	_, err := senders[domain.ChannelTypeTelegram].Send(context.Background(), ch, testMessage())
+	if err == nil {
+		t.Fatal("send to closed server succeeded")
+	}
+	if strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("error reveals bot token: %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/wrtgvr/websites-monitor/internal/config"
+	"github.com/wrtgvr/websites-monitor/internal/domain"
+	errs "github.com/wrtgvr/websites-monitor/internal/errors"
+	"github.com/wrtgvr/websites-monitor/internal/storage"
+)
+
+// Max due escalations loaded from storage at once
+const escalationBatchSize = 100
+
+// Runs due steps of escalation policies of open incidents.
+// Pending escalations are kept in storage, so escalation goes on after restart
+type Escalator struct {
+	storage  storage.Storage
+	notifier *Notifier
+	interval time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewEscalator(storage storage.Storage, notifier *Notifier, cfg *config.NotifyConfig) *Escalator {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Escalator{
+		storage:  storage,
+		notifier: notifier,
+		interval: cfg.EscalationInterval,
+		ctx:      ctx,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+}
+
+// Run escalator until `Stop()` is called. Blocks
+func (e *Escalator) Run() {
+	defer close(e.done)
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		e.escalateDue(e.ctx)
+
+		select {
+		case <-e.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop escalator and wait for running step to finish.
+// Must be called only after `Run()` was started
+func (e *Escalator) Stop() {
+	e.cancel()
+	<-e.done
+}
+
+func (e *Escalator) escalateDue(ctx context.Context) {
+	now := time.Now()
+	due, appErr := e.storage.GetDueEscalations(ctx, now, escalationBatchSize)
+	if appErr != nil {
+		log.Printf("ERR: failed to get due escalations, err=%v\n", appErr)
+		return
+	}
+	for _, esc := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		e.escalate(ctx, esc, now)
+	}
+}
+
+// Run due step of escalation and schedule next one. Escalation of incident that is
+// not open anymore, or of deleted policy, is dropped
+func (e *Escalator) escalate(ctx context.Context, esc *domain.Escalation, now time.Time) {
+	inc, appErr := e.storage.GetIncident(ctx, esc.ProjectId, esc.IncidentID)
+	if appErr != nil {
+		if appErr.Type == errs.TypeNotFound {
+			e.drop(ctx, esc)
+			return
+		}
+		log.Printf("ERR: failed to get incident, project_id=%s, incident_id=%s, err=%v\n", esc.ProjectId, esc.IncidentID, appErr)
+		return
+	}
+	if inc.State != domain.IncidentOpen {
+		e.drop(ctx, esc)
+		return
+	}
+
+	policy, appErr := e.storage.GetEscalationPolicy(ctx, esc.ProjectId, esc.PolicyID)
+	if appErr != nil {
+		if appErr.Type == errs.TypeNotFound {
+			e.drop(ctx, esc)
+			return
+		}
+		log.Printf("ERR: failed to get escalation policy, project_id=%s, policy_id=%s, err=%v\n", esc.ProjectId, esc.PolicyID, appErr)
+		return
+	}
+	if len(policy.Steps) == 0 {
+		e.drop(ctx, esc)
+		return
+	}
+
+	//* schedule next step first, so failed storage doesn't repeat current one every tick
+	i, step := policy.Current(esc)
+	round := esc.Round
+	policy.Advance(esc, now)
+	if appErr := e.storage.ScheduleEscalation(ctx, esc); appErr != nil {
+		log.Printf("ERR: failed to schedule escalation, project_id=%s, incident_id=%s, err=%v\n", esc.ProjectId, esc.IncidentID, appErr)
+		return
+	}
+
+	//* notify step channels, endpoint could be deleted while incident is open
+	var ep *domain.Endpoint
+	if eps, appErr := e.storage.GetEndpoints(ctx, esc.ProjectId, inc.EndpointID); appErr == nil && len(eps) > 0 {
+		ep = eps[0]
+	}
+	change := domain.NewEscalationChange(inc, ep, i+1, now)
+	change.OnCall = e.onCall(ctx, esc.ProjectId, step.ScheduleIDs, now)
+	e.notifier.NotifyChannels(change, step.ChannelIDs)
+
+	text := fmt.Sprintf("step %d of escalation policy %q, round %d, channels: %d", i+1, policy.Name, round+1, len(step.ChannelIDs))
+	if len(change.OnCall) > 0 {
+		names := make([]string, len(change.OnCall))
+		for j, p := range change.OnCall {
+			names[j] = p.Name
+		}
+		text += ", on call: " + strings.Join(names, ", ")
+	}
+	entry := &domain.TimelineEntry{
+		At:      now,
+		Type:    domain.TimelineEscalation,
+		Message: text,
+	}
+	if appErr := e.storage.AddIncidentTimelineEntry(ctx, esc.ProjectId, esc.IncidentID, entry); appErr != nil && appErr.Type != errs.TypeNotFound {
+		log.Printf("ERR: failed to add incident timeline entry, incident_id=%s, err=%v\n", esc.IncidentID, appErr)
+	}
+}
+
+// Participants on call at `now` in schedules of step, without duplicates.
+// Deleted schedules and schedules nobody is on call in are skipped
+func (e *Escalator) onCall(ctx context.Context, projectId string, scheduleIds []string, now time.Time) []domain.OnCallParticipant {
+	var participants []domain.OnCallParticipant
+	for _, id := range scheduleIds {
+		schedule, appErr := e.storage.GetSchedule(ctx, projectId, id)
+		if appErr != nil {
+			if appErr.Type == errs.TypeNotFound {
+				log.Printf("WARN: on-call schedule of escalation step not found, project_id=%s, schedule_id=%s\n", projectId, id)
+			} else {
+				log.Printf("ERR: failed to get on-call schedule, project_id=%s, schedule_id=%s, err=%v\n", projectId, id, appErr)
+			}
+			continue
+		}
+		overrides, appErr := e.storage.GetScheduleOverrides(ctx, projectId, id, now)
+		if appErr != nil {
+			log.Printf("ERR: failed to get schedule overrides, project_id=%s, schedule_id=%s, err=%v\n", projectId, id, appErr)
+		}
+
+		shift := schedule.OnCallAt(now, overrides)
+		if shift == nil || slices.Contains(participants, shift.Participant) {
+			continue
+		}
+		participants = append(participants, shift.Participant)
+	}
+	return participants
+}
+
+func (e *Escalator) drop(ctx context.Context, esc *domain.Escalation) {
+	if appErr := e.storage.DeleteEscalation(ctx, esc.ProjectId, esc.IncidentID); appErr != nil {
+		log.Printf("ERR: failed to delete escalation, project_id=%s, incident_id=%s, err=%v\n", esc.ProjectId, esc.IncidentID, appErr)
+	}
+}
@@ -0,0 +1,106 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wrtgvr/websites-monitor/internal/domain"
+)
+
+// Human readable summary of state change shared by channel formatters
+type summary struct {
+	Title string
+	// Endpoint name, id if endpoint has no name
+	Name string
+	// Empty for heartbeat endpoints
+	URL  string
+	From string
+	To   string
+	At   string
+	// Failure reason of down event
+	Reason string
+	// Time endpoint was down, empty for down event
+	Downtime string
+	Link     string
+	Up       bool
+	// Names of participants on call for escalation step, empty if there are none
+	OnCall string
+}
+
+func summarize(msg *message) *summary {
+	s := &summary{
+		Name: msg.EndpointName,
+		URL:  msg.EndpointURL,
+		From: string(msg.From),
+		To:   string(msg.To),
+		At:   msg.At.UTC().Format(time.RFC1123),
+		Link: msg.Link,
+		Up:   msg.Event == domain.EventEndpointUp,
+	}
+	if s.Name == "" {
+		s.Name = msg.EndpointID
+	}
+
+	if s.Up {
+		s.Title = fmt.Sprintf("%s is UP", s.Name)
+		s.Downtime = formatDowntime(msg.Downtime)
+		return s
+	}
+	s.Title = fmt.Sprintf("%s is DOWN", s.Name)
+	if msg.Event == domain.EventIncidentEscalated {
+		s.Title = fmt.Sprintf("%s is still DOWN, escalation step %d", s.Name, msg.EscalationStep)
+	}
+	names := make([]string, len(msg.OnCall))
+	for i, p := range msg.OnCall {
+		names[i] = p.Name
+	}
+	s.OnCall = strings.Join(names, ", ")
+	s.Reason = msg.CheckResult.Reason()
+	if s.Reason == "" {
+		s.Reason = "unknown"
+	}
+	return s
+}
+
+// Downtime rounded to seconds, `unknown` if it is not known
+func formatDowntime(d time.Duration) string {
+	if d <= 0 {
+		return "unknown"
+	}
+	return d.Round(time.Second).String()
+}
+
+// Named value shown in chat message
+type fact struct {
+	Name  string
+	Value string
+}
+
+// Details of state change in display order
+func (s *summary) facts() []fact {
+	facts := []fact{{"Endpoint", s.Name}}
+	if s.URL != "" {
+		facts = append(facts, fact{"URL", s.URL})
+	}
+	facts = append(facts, fact{"State", s.From + " → " + s.To})
+	if s.Up {
+		return append(facts, fact{"Down for", s.Downtime})
+	}
+	facts = append(facts,
+		fact{"Reason", s.Reason},
+		fact{"Down since", s.At})
+	if s.OnCall != "" {
+		facts = append(facts, fact{"On call", s.OnCall})
+	}
+	return facts
+}
+
+// Cut string to at most `n` runes, marking cut with ellipsis
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,220 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wrtgvr/websites-monitor/internal/config"
+	"github.com/wrtgvr/websites-monitor/internal/domain"
+	errs "github.com/wrtgvr/websites-monitor/internal/errors"
+	"github.com/wrtgvr/websites-monitor/internal/storage"
+)
+
+// Delivers single notification to channel of one type
+type sender interface {
+	// Returns http status or smtp reply code of response, zero if there is none
+	Send(ctx context.Context, ch *domain.NotificationChannel, msg *message) (int, error)
+}
+
+var senders = map[domain.ChannelType]sender{
+	domain.ChannelTypeWebhook:  newWebhookSender(),
+	domain.ChannelTypeSMTP:     &smtpSender{},
+	domain.ChannelTypeSlack:    newChatSender(webhookURL, slackPayload),
+	domain.ChannelTypeDiscord:  newChatSender(webhookURL, discordPayload),
+	domain.ChannelTypeTeams:    newChatSender(webhookURL, teamsPayload),
+	domain.ChannelTypeTelegram: newChatSender(telegramURL, telegramPayload),
+}
+
+// Notification of state change, same for all attempts
+type message struct {
+	// Delivery id, lets receiver drop duplicates of retried notification
+	ID string
+	// Empty if link is not configured
+	Link string
+	*domain.StateChange
+}
+
+// Notifies project channels about endpoint state changes.
+// Every channel is notified independently, failed deliveries are retried with exponential backoff
+type Notifier struct {
+	storage       storage.Storage
+	maxAttempts   int
+	retryDelay    time.Duration
+	maxRetryDelay time.Duration
+	timeout       time.Duration
+	linkURL       string
+
+	wg sync.WaitGroup
+	// set by `Stop()`, no deliveries are started after it
+	mu     sync.Mutex
+	closed bool
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func NewNotifier(storage storage.Storage, cfg *config.NotifyConfig) *Notifier {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Notifier{
+		storage:       storage,
+		maxAttempts:   max(cfg.MaxAttempts, 1),
+		retryDelay:    cfg.RetryDelay,
+		maxRetryDelay: cfg.MaxRetryDelay,
+		timeout:       cfg.Timeout,
+		linkURL:       cfg.LinkURL,
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+}
+
+// Notify enabled channels of project about state change. Doesn't block
+func (n *Notifier) Notify(change *domain.StateChange) {
+	n.start(func() {
+		n.dispatch(n.ctx, change, nil)
+	})
+}
+
+// Notify enabled channels with given ids about state change. Doesn't block
+func (n *Notifier) NotifyChannels(change *domain.StateChange, channelIds []string) {
+	n.start(func() {
+		n.dispatch(n.ctx, change, channelIds)
+	})
+}
+
+// Stop retries and wait for running deliveries to finish
+func (n *Notifier) Stop() {
+	n.mu.Lock()
+	n.closed = true
+	n.mu.Unlock()
+
+	n.cancel()
+	n.wg.Wait()
+}
+
+// Run `fn` in goroutine `Stop()` waits for. No-op after `Stop()` was called
+func (n *Notifier) start(fn func()) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.closed {
+		return
+	}
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		fn()
+	}()
+}
+
+// Deliver change to enabled project channels, only to listed ones if `channelIds` is not nil
+func (n *Notifier) dispatch(ctx context.Context, change *domain.StateChange, channelIds []string) {
+	channels, appErr := n.storage.GetNotificationChannels(ctx, change.ProjectId)
+	if appErr != nil {
+		log.Printf("ERR: failed to get notification channels, project_id=%s, err=%v\n", change.ProjectId, appErr)
+		return
+	}
+
+	msg := &message{
+		ID:          uuid.NewString(),
+		Link:        n.link(change),
+		StateChange: change,
+	}
+	for _, ch := range channels {
+		if !ch.Enabled || (channelIds != nil && !slices.Contains(channelIds, ch.ID)) {
+			continue
+		}
+		n.wg.Add(1)
+		go func() {
+			defer n.wg.Done()
+			n.deliver(ctx, ch, msg)
+		}()
+	}
+}
+
+// Link of changed endpoint built from link template
+func (n *Notifier) link(change *domain.StateChange) string {
+	if n.linkURL == "" {
+		return ""
+	}
+	return strings.NewReplacer(
+		"{project_id}", url.PathEscape(change.ProjectId),
+		"{endpoint_id}", url.PathEscape(change.EndpointID),
+	).Replace(n.linkURL)
+}
+
+// Send message to channel until it is delivered or attempts run out.
+// Every attempt is saved to channel delivery log
+func (n *Notifier) deliver(ctx context.Context, ch *domain.NotificationChannel, msg *message) {
+	s, ok := senders[ch.ChannelType()]
+	if !ok {
+		log.Printf("WARN: unknown notification channel type, channel_id=%s, type=%s\n", ch.ID, ch.Type)
+		return
+	}
+
+	delay := n.retryDelay
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		attemptCtx, cancel := context.WithTimeout(ctx, n.timeout)
+		code, err := s.Send(attemptCtx, ch, msg)
+		cancel()
+
+		d := &domain.Delivery{
+			ID:         msg.ID,
+			ChannelID:  ch.ID,
+			Event:      msg.Event,
+			EndpointID: msg.EndpointID,
+			Attempt:    attempt,
+			At:         start,
+			Duration:   time.Since(start),
+			StatusCode: code,
+			Success:    err == nil,
+		}
+		if err != nil {
+			d.Error = err.Error()
+		}
+		// attempt is logged even if notifier is stopping
+		if appErr := n.storage.AddNotificationDelivery(context.WithoutCancel(ctx), ch.ProjectId, d); appErr != nil {
+			log.Printf("ERR: failed to save delivery, channel_id=%s, err=%v\n", ch.ID, appErr)
+		}
+
+		if err == nil {
+			n.addTimelineEntry(ctx, ch, msg, fmt.Sprintf("%s sent to %s channel %q, attempts: %d", msg.Event, ch.ChannelType(), ch.Name, attempt))
+			return
+		}
+		if attempt >= n.maxAttempts {
+			log.Printf("WARN: notification was not delivered, channel_id=%s, delivery_id=%s, attempts=%d, err=%v\n", ch.ID, msg.ID, attempt, err)
+			n.addTimelineEntry(ctx, ch, msg, fmt.Sprintf("%s was not delivered to %s channel %q, attempts: %d, error: %v", msg.Event, ch.ChannelType(), ch.Name, attempt, err))
+			return
+		}
+
+		//* wait before retry
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		delay = min(delay*2, n.maxRetryDelay)
+	}
+}
+
+// Add delivery outcome to timeline of incident message belongs to
+func (n *Notifier) addTimelineEntry(ctx context.Context, ch *domain.NotificationChannel, msg *message, text string) {
+	if msg.IncidentID == "" {
+		return
+	}
+	entry := &domain.TimelineEntry{
+		At:      time.Now(),
+		Type:    domain.TimelineNotification,
+		Message: text,
+	}
+	if appErr := n.storage.AddIncidentTimelineEntry(context.WithoutCancel(ctx), ch.ProjectId, msg.IncidentID, entry); appErr != nil && appErr.Type != errs.TypeNotFound {
+		log.Printf("ERR: failed to add incident timeline entry, incident_id=%s, err=%v\n", msg.IncidentID, appErr)
+	}
+}
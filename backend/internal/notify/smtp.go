@@ -0,0 +1,213 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/wrtgvr/websites-monitor/internal/domain"
+)
+
+var emailTextTemplate = template.Must(template.New("text").Parse(`{{.Title}}
+
+Endpoint: {{.Name}}
+{{if .URL}}URL: {{.URL}}
+{{end}}State: {{.From}} -> {{.To}}
+{{if .Up}}Down for: {{.Downtime}}
+{{else}}Reason: {{.Reason}}
+Down since: {{.At}}
+{{end}}{{if .OnCall}}On call: {{.OnCall}}
+{{end}}{{if .Link}}
+{{.Link}}
+{{end}}`))
+
+var emailHTMLTemplate = htmltemplate.Must(htmltemplate.New("html").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif;">
+<h2 style="color: {{if .Up}}#2e7d32{{else}}#c62828{{end}};">{{.Title}}</h2>
+<table cellpadding="4">
+<tr><td><b>Endpoint</b></td><td>{{.Name}}</td></tr>
+{{if .URL}}<tr><td><b>URL</b></td><td>{{.URL}}</td></tr>
+{{end}}<tr><td><b>State</b></td><td>{{.From}} &rarr; {{.To}}</td></tr>
+{{if .Up}}<tr><td><b>Down for</b></td><td>{{.Downtime}}</td></tr>
+{{else}}<tr><td><b>Reason</b></td><td>{{.Reason}}</td></tr>
+<tr><td><b>Down since</b></td><td>{{.At}}</td></tr>
+{{end}}{{if .OnCall}}<tr><td><b>On call</b></td><td>{{.OnCall}}</td></tr>
+{{end}}</table>
+{{if .Link}}<p><a href="{{.Link}}">View endpoint</a></p>
+{{end}}</body>
+</html>
+`))
+
+// Sends email with plain text and html bodies
+type smtpSender struct{}
+
+// Returns smtp reply code of failed command, zero on success
+func (s *smtpSender) Send(ctx context.Context, ch *domain.NotificationChannel, msg *message) (int, error) {
+	from, err := mail.ParseAddress(ch.SMTPFrom)
+	if err != nil {
+		return 0, err
+	}
+	to := recipients(ch, msg)
+	email, err := buildEmail(ch, msg, to)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := sendMail(ctx, ch, from.Address, to, email); err != nil {
+		var tpErr *textproto.Error
+		if errors.As(err, &tpErr) {
+			return tpErr.Code, err
+		}
+		return 0, err
+	}
+	return 0, nil
+}
+
+// Emails of participants on call, channel recipients if nobody on call has email
+func recipients(ch *domain.NotificationChannel, msg *message) []string {
+	var to []string
+	for _, p := range msg.OnCall {
+		if p.Email != "" {
+			to = append(to, (&mail.Address{Name: p.Name, Address: p.Email}).String())
+		}
+	}
+	if len(to) == 0 {
+		return ch.SMTPRecipients
+	}
+	return to
+}
+
+func sendMail(ctx context.Context, ch *domain.NotificationChannel, from string, to []string, email []byte) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(ch.SMTPHost, strconv.Itoa(ch.SMTPPort)))
+	if err != nil {
+		return err
+	}
+	// smtp client has no context support
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, ch.SMTPHost)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ch.SMTPStartTLS {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return errors.New("smtp server does not support STARTTLS")
+		}
+		if err := c.StartTLS(&tls.Config{ServerName: ch.SMTPHost}); err != nil {
+			return err
+		}
+	}
+	if ch.SMTPUsername != "" {
+		// channel validation ensures connection is encrypted or host is local
+		if err := c.Auth(smtp.PlainAuth("", ch.SMTPUsername, ch.SMTPPassword, ch.SMTPHost)); err != nil {
+			return err
+		}
+	}
+
+	//* envelope
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, r := range to {
+		addr, err := mail.ParseAddress(r)
+		if err != nil {
+			return err
+		}
+		if err := c.Rcpt(addr.Address); err != nil {
+			return err
+		}
+	}
+
+	//* message
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(email); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// Build multipart/alternative email with plain text and html bodies
+func buildEmail(ch *domain.NotificationChannel, msg *message, to []string) ([]byte, error) {
+	sum := summarize(msg)
+
+	var text, html bytes.Buffer
+	if err := emailTextTemplate.Execute(&text, sum); err != nil {
+		return nil, err
+	}
+	if err := emailHTMLTemplate.Execute(&html, sum); err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	if err := writeEmailPart(mw, "text/plain; charset=utf-8", text.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := writeEmailPart(mw, "text/html; charset=utf-8", html.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	//* headers
+	var email bytes.Buffer
+	headers := []struct{ name, value string }{
+		{"From", ch.SMTPFrom},
+		{"To", strings.Join(to, ", ")},
+		{"Subject", mime.QEncoding.Encode("utf-8", sum.Title)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-ID", fmt.Sprintf("<%s.%s@websites-monitor>", msg.ID, ch.ID)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "multipart/alternative; boundary=" + mw.Boundary()},
+	}
+	for _, h := range headers {
+		fmt.Fprintf(&email, "%s: %s\r\n", h.name, h.value)
+	}
+	email.WriteString("\r\n")
+	email.Write(body.Bytes())
+	return email.Bytes(), nil
+}
+
+func writeEmailPart(mw *multipart.Writer, contentType string, content []byte) error {
+	part, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+	qp := quotedprintable.NewWriter(part)
+	if _, err := io.Copy(qp, bytes.NewReader(content)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
@@ -0,0 +1,195 @@
+package notify
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wrtgvr/websites-monitor/internal/domain"
+)
+
+// Mail transaction received by test smtp server
+type receivedMail struct {
+	from string
+	to   []string
+	data []byte
+}
+
+// Start smtp server that accepts single session and replies with `rcptCode` to RCPT commands.
+// Returns server host and port
+func startSMTPServer(t *testing.T, rcptCode int) (string, int, <-chan receivedMail) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	received := make(chan receivedMail, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+		serveSMTP(textproto.NewConn(conn), rcptCode, received)
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, received
+}
+
+func serveSMTP(c *textproto.Conn, rcptCode int, received chan<- receivedMail) {
+	var m receivedMail
+	c.PrintfLine("220 localhost ESMTP test")
+	for {
+		line, err := c.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(cmd) {
+		case "EHLO", "HELO":
+			c.PrintfLine("250 localhost")
+		case "MAIL":
+			m.from = smtpPath(arg)
+			c.PrintfLine("250 OK")
+		case "RCPT":
+			if rcptCode != 250 {
+				c.PrintfLine("%d recipient rejected", rcptCode)
+				continue
+			}
+			m.to = append(m.to, smtpPath(arg))
+			c.PrintfLine("250 OK")
+		case "DATA":
+			c.PrintfLine("354 end data with <CR><LF>.<CR><LF>")
+			if m.data, err = c.ReadDotBytes(); err != nil {
+				return
+			}
+			c.PrintfLine("250 OK")
+			received <- m
+		case "QUIT":
+			c.PrintfLine("221 bye")
+			return
+		default:
+			c.PrintfLine("502 command not implemented")
+		}
+	}
+}
+
+// Address of `FROM:<addr>` or `TO:<addr>` argument
+func smtpPath(arg string) string {
+	_, path, _ := strings.Cut(arg, ":")
+	return strings.Trim(path, "<>")
+}
+
+func testSMTPChannel(host string, port int) *domain.NotificationChannel {
+	return &domain.NotificationChannel{
+		ID:             "ch",
+		Type:           domain.ChannelTypeSMTP,
+		SMTPHost:       host,
+		SMTPPort:       port,
+		SMTPFrom:       "Monitor <monitor@example.com>",
+		SMTPRecipients: []string{"ops@example.com", "Dev <dev@example.com>"},
+	}
+}
+
+func TestSMTPSender(t *testing.T) {
+	host, port, received := startSMTPServer(t, 250)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if code, err := (&smtpSender{}).Send(ctx, testSMTPChannel(host, port), testMessage()); err != nil || code != 0 {
+		t.Fatalf("got code %d, err %v", code, err)
+	}
+	m := <-received
+
+	//* envelope
+	if m.from != "monitor@example.com" {
+		t.Errorf("got sender %q", m.from)
+	}
+	if strings.Join(m.to, ",") != "ops@example.com,dev@example.com" {
+		t.Errorf("got recipients %q", m.to)
+	}
+
+	//* headers
+	email, err := mail.ReadMessage(strings.NewReader(string(m.data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(email.Header.Get("Subject"))
+	if err != nil || subject != "api is DOWN" {
+		t.Errorf("got subject %q, err %v", subject, err)
+	}
+	if got := email.Header.Get("To"); got != "ops@example.com, Dev <dev@example.com>" {
+		t.Errorf("got To header %q", got)
+	}
+	if got := email.Header.Get("Message-ID"); got != "<delivery-1.ch@websites-monitor>" {
+		t.Errorf("got Message-ID header %q", got)
+	}
+
+	//* text and html parts
+	mediaType, params, err := mime.ParseMediaType(email.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("got content type %q, err %v", mediaType, err)
+	}
+	parts := map[string]string{}
+	mr := multipart.NewReader(email.Body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(part)
+		parts[part.Header.Get("Content-Type")] = string(b)
+	}
+	text := parts["text/plain; charset=utf-8"]
+	for _, want := range []string{"Endpoint: api", "State: up -> down", "Reason: status_code: unexpected status code 503", "https://monitor.example.com/p/ep"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text part does not contain %q:\n%s", want, text)
+		}
+	}
+	html := parts["text/html; charset=utf-8"]
+	for _, want := range []string{"<td>api</td>", "up &rarr; down", `<a href="https://monitor.example.com/p/ep">`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("html part does not contain %q:\n%s", want, html)
+		}
+	}
+}
+
+func TestSMTPSenderOnCallRecipients(t *testing.T) {
+	host, port, received := startSMTPServer(t, 250)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	msg := testMessage()
+	msg.OnCall = []domain.OnCallParticipant{{Name: "Alice"}, {Name: "Bob", Email: "bob@example.com"}}
+	if _, err := (&smtpSender{}).Send(ctx, testSMTPChannel(host, port), msg); err != nil {
+		t.Fatal(err)
+	}
+	if m := <-received; strings.Join(m.to, ",") != "bob@example.com" {
+		t.Errorf("got recipients %q, want only participant with email", m.to)
+	}
+}
+
+func TestSMTPSenderRejectedRecipient(t *testing.T) {
+	host, port, _ := startSMTPServer(t, 550)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	code, err := (&smtpSender{}).Send(ctx, testSMTPChannel(host, port), testMessage())
+	if err == nil || code != 550 {
+		t.Fatalf("got code %d, err %v; want code 550 and error", code, err)
+	}
+}
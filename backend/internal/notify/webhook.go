@@ -0,0 +1,163 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"time"
+
+	"github.com/wrtgvr/websites-monitor/internal/domain"
+)
+
+// Webhook request headers
+const (
+	headerEvent     = "X-Monitor-Event"
+	headerDelivery  = "X-Monitor-Delivery"
+	headerSignature = "X-Monitor-Signature-256"
+)
+
+type webhookPayload struct {
+	// Delivery id, same for retries of one notification
+	ID            string          `json:"id"`
+	Event         string          `json:"event"`
+	ProjectID     string          `json:"project_id"`
+	Endpoint      webhookEndpoint `json:"endpoint"`
+	PreviousState string          `json:"previous_state"`
+	State         string          `json:"state"`
+	At            string          `json:"at"`
+	StatusCode    int             `json:"status_code,omitempty"`
+	ErrorClass    string          `json:"error_class,omitempty"`
+	Error         string          `json:"error,omitempty"`
+	DurationMs    float64         `json:"duration_ms"`
+	// endpoint.up and incident.escalated only
+	DowntimeSec float64 `json:"downtime_sec,omitempty"`
+	Link        string  `json:"link,omitempty"`
+	IncidentID  string  `json:"incident_id,omitempty"`
+	// incident.escalated only
+	EscalationStep int `json:"escalation_step,omitempty"`
+	// Participants on call for escalation step
+	OnCall []webhookParticipant `json:"on_call,omitempty"`
+}
+
+type webhookParticipant struct {
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+}
+
+type webhookEndpoint struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	URL  string `json:"url,omitempty"`
+}
+
+// Posts JSON payload signed with HMAC-SHA256 of channel secret
+type webhookSender struct {
+	client *http.Client
+}
+
+func newWebhookSender() *webhookSender {
+	return &webhookSender{
+		client: newClient(),
+	}
+}
+
+func (s *webhookSender) Send(ctx context.Context, ch *domain.NotificationChannel, msg *message) (int, error) {
+	body, err := json.Marshal(&webhookPayload{
+		ID:        msg.ID,
+		Event:     string(msg.Event),
+		ProjectID: msg.ProjectId,
+		Endpoint: webhookEndpoint{
+			ID:   msg.EndpointID,
+			Name: msg.EndpointName,
+			Type: string(msg.EndpointType),
+			URL:  msg.EndpointURL,
+		},
+		PreviousState:  string(msg.From),
+		State:          string(msg.To),
+		At:             msg.At.Format(time.RFC3339),
+		StatusCode:     msg.StatusCode,
+		ErrorClass:     string(msg.ErrorClass),
+		Error:          msg.Error,
+		DurationMs:     domain.Milliseconds(msg.Duration),
+		DowntimeSec:    msg.Downtime.Seconds(),
+		Link:           msg.Link,
+		IncidentID:     msg.IncidentID,
+		EscalationStep: msg.EscalationStep,
+		OnCall:         webhookParticipants(msg.OnCall),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return postJSON(ctx, s.client, ch.WebhookURL, body, map[string]string{
+		headerEvent:     string(msg.Event),
+		headerDelivery:  msg.ID,
+		headerSignature: "sha256=" + sign(ch.WebhookSecret, body),
+	})
+}
+
+func webhookParticipants(participants []domain.OnCallParticipant) []webhookParticipant {
+	if len(participants) == 0 {
+		return nil
+	}
+	res := make([]webhookParticipant, len(participants))
+	for i, p := range participants {
+		res[i] = webhookParticipant{Name: p.Name, Email: p.Email}
+	}
+	return res
+}
+
+// Post JSON body with extra headers. Response with non-2xx status code is an error
+func postJSON(ctx context.Context, client *http.Client, url string, body []byte, headers map[string]string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// url is left out of error, it holds secrets of chat webhooks and bot tokens
+		var urlErr *neturl.Error
+		if errors.As(err, &urlErr) {
+			return 0, urlErr.Err
+		}
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// drain body so connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Client that doesn't follow redirects, redirect of POST request would lose body
+func newClient() *http.Client {
+	return &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// Hex encoded HMAC-SHA256 of body
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,155 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/wrtgvr/websites-monitor/internal/config"
+	"github.com/wrtgvr/websites-monitor/internal/domain"
+)
+
+// Message of endpoint going down at fixed time
+func testMessage() *message {
+	return &message{
+		ID:   "delivery-1",
+		Link: "https://monitor.example.com/p/ep",
+		StateChange: &domain.StateChange{
+			Event:        domain.EventEndpointDown,
+			ProjectId:    "p",
+			EndpointID:   "ep",
+			EndpointName: "api",
+			EndpointType: domain.CheckTypeHTTP,
+			EndpointURL:  "https://api.example.com/health",
+			From:         domain.StateUp,
+			To:           domain.StateDown,
+			At:           time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			CheckResult: domain.CheckResult{
+				State:      domain.StateDown,
+				StatusCode: 503,
+				ErrorClass: domain.ErrorClassStatusCode,
+				Error:      "unexpected status code 503",
+				Duration:   120 * time.Millisecond,
+			},
+			IncidentID: "inc-1",
+		},
+	}
+}
+
+// Request received by test server
+type receivedRequest struct {
+	path   string
+	header http.Header
+	body   []byte
+}
+
+// Start server that records requests and responds with given status code
+func startReceiver(t *testing.T, status int) (*httptest.Server, <-chan receivedRequest) {
+	t.Helper()
+	received := make(chan receivedRequest, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- receivedRequest{path: r.URL.Path, header: r.Header.Clone(), body: body}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, received
+}
+
+func TestWebhookSender(t *testing.T) {
+	srv, received := startReceiver(t, http.StatusNoContent)
+	ch := &domain.NotificationChannel{
+		Type:          domain.ChannelTypeWebhook,
+		WebhookURL:    srv.URL + "/hook",
+		WebhookSecret: "s3cret",
+	}
+
+	code, err := newWebhookSender().Send(context.Background(), ch, testMessage())
+	if err != nil || code != http.StatusNoContent {
+		t.Fatalf("got code %d, err %v; want %d", code, err, http.StatusNoContent)
+	}
+	req := <-received
+
+	//* headers
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(req.body)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); req.header.Get(headerSignature) != want {
+		t.Errorf("got signature %q, want %q", req.header.Get(headerSignature), want)
+	}
+	if got := req.header.Get(headerEvent); got != string(domain.EventEndpointDown) {
+		t.Errorf("got event header %q", got)
+	}
+	if got := req.header.Get(headerDelivery); got != "delivery-1" {
+		t.Errorf("got delivery header %q", got)
+	}
+	if got := req.header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("got content type %q", got)
+	}
+
+	//* payload
+	var payload webhookPayload
+	if err := json.Unmarshal(req.body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	want := webhookPayload{
+		ID:        "delivery-1",
+		Event:     "endpoint.down",
+		ProjectID: "p",
+		Endpoint: webhookEndpoint{
+			ID:   "ep",
+			Name: "api",
+			Type: "http",
+			URL:  "https://api.example.com/health",
+		},
+		PreviousState: "up",
+		State:         "down",
+		At:            "2024-01-01T12:00:00Z",
+		StatusCode:    503,
+		ErrorClass:    "status_code",
+		Error:         "unexpected status code 503",
+		DurationMs:    120,
+		Link:          "https://monitor.example.com/p/ep",
+		IncidentID:    "inc-1",
+	}
+	if got, _ := json.Marshal(payload); string(got) != string(mustMarshal(t, want)) {
+		t.Errorf("got payload %s, want %s", got, mustMarshal(t, want))
+	}
+}
+
+func TestWebhookSenderUnexpectedStatus(t *testing.T) {
+	for _, status := range []int{http.StatusMovedPermanently, http.StatusBadRequest, http.StatusInternalServerError} {
+		srv, _ := startReceiver(t, status)
+		ch := &domain.NotificationChannel{Type: domain.ChannelTypeWebhook, WebhookURL: srv.URL}
+
+		code, err := newWebhookSender().Send(context.Background(), ch, testMessage())
+		if err == nil || code != status {
+			t.Errorf("got code %d, err %v; want code %d and error", code, err, status)
+		}
+	}
+}
+
+func TestNotifierDoesNotStartAfterStop(t *testing.T) {
+	// storage is nil, so any started dispatch panics
+	n := NewNotifier(nil, &config.NotifyConfig{})
+	n.Stop()
+
+	n.Notify(testMessage().StateChange)
+	n.NotifyChannels(testMessage().StateChange, []string{"ch"})
+	n.wg.Wait()
+}
+
+func mustMarshal(t *testing.T, v any) []byte {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
@@ -25,10 +25,197 @@ const (
 	EndpointInfo_HSet_Url = "url"
 	// Endpoint info HSet field for project id
 	EndpointInfo_HSet_ProjectId = "project_id"
-	// Endpoint status HSet field for status
-	EndpointStatus_HSet_Status = "status"
-	// Endpoint status HSet field for last checked time
+	// Endpoint info HSet field for check type
+	EndpointInfo_HSet_Type = "type"
+	// Endpoint info HSet field for SLO target percentage
+	EndpointInfo_HSet_SLOTarget = "slo_target"
+	// Endpoint info HSet field for escalation policy id, empty if endpoint has none
+	EndpointInfo_HSet_EscalationPolicyID = "escalation_policy_id"
+	// Endpoint info HSet field for check interval in seconds
+	EndpointInfo_HSet_Interval = "interval"
+	// Endpoint info HSet field for check timeout in milliseconds
+	EndpointInfo_HSet_Timeout = "timeout"
+	// Endpoint info HSet field for amount of check retries
+	EndpointInfo_HSet_Retries = "retries"
+	// Endpoint info HSet field for consecutive failed checks before endpoint is down
+	EndpointInfo_HSet_FailureThreshold = "failure_threshold"
+	// Endpoint info HSet field for consecutive successful checks before endpoint is up
+	EndpointInfo_HSet_RecoveryThreshold = "recovery_threshold"
+	// Endpoint info HSet field for re-check interval in seconds
+	EndpointInfo_HSet_RecheckInterval = "recheck_interval"
+	// Endpoint info HSet field for http check method
+	EndpointInfo_HSet_Method = "method"
+	// Endpoint info HSet field for http check headers, JSON array
+	EndpointInfo_HSet_Headers = "headers"
+	// Endpoint info HSet field for http check request body
+	EndpointInfo_HSet_Body = "body"
+	// Endpoint info HSet field for expected status codes, e.g. `200-299,401`
+	EndpointInfo_HSet_ExpectedStatuses = "expected_statuses"
+	// Endpoint info HSet field for redirect policy
+	EndpointInfo_HSet_RedirectPolicy = "redirect_policy"
+	// Endpoint info HSet field for response assertions, JSON array
+	EndpointInfo_HSet_Assertions = "assertions"
+	// Endpoint info HSet field for tcp check payload
+	EndpointInfo_HSet_TCPPayload = "tcp_payload"
+	// Endpoint info HSet field for tcp check expected banner prefix
+	EndpointInfo_HSet_TCPExpectBanner = "tcp_expect_banner"
+	// Endpoint info HSet field for dns check record type
+	EndpointInfo_HSet_DNSRecordType = "dns_record_type"
+	// Endpoint info HSet field for dns check resolver address
+	EndpointInfo_HSet_DNSResolver = "dns_resolver"
+	// Endpoint info HSet field for dns check expected values, JSON array
+	EndpointInfo_HSet_DNSExpected = "dns_expected"
+	// Endpoint info HSet field for grpc check service name
+	EndpointInfo_HSet_GRPCService = "grpc_service"
+	// Endpoint info HSet field for grpc check tls mode, "1" or "0"
+	EndpointInfo_HSet_GRPCTLS = "grpc_tls"
+	// Endpoint info HSet field for websocket check message
+	EndpointInfo_HSet_WSMessage = "ws_message"
+	// Endpoint info HSet field for websocket check reply pattern
+	EndpointInfo_HSet_WSExpectPattern = "ws_expect_pattern"
+	// Endpoint info HSet field for heartbeat check token
+	EndpointInfo_HSet_HeartbeatToken = "heartbeat_token"
+	// Endpoint info HSet field for heartbeat period in seconds
+	EndpointInfo_HSet_HeartbeatPeriod = "heartbeat_period"
+	// Endpoint info HSet field for heartbeat grace in seconds
+	EndpointInfo_HSet_HeartbeatGrace = "heartbeat_grace"
+	// Endpoint info HSet field for certificate expiry threshold in days
+	EndpointInfo_HSet_TLSExpiryThresholdDays = "tls_expiry_threshold_days"
+	// Endpoint status HSet field for state
+	EndpointStatus_HSet_State = "state"
+	// Endpoint status HSet field for http status code
+	EndpointStatus_HSet_StatusCode = "status_code"
+	// Endpoint status HSet field for error class
+	EndpointStatus_HSet_ErrorClass = "error_class"
+	// Endpoint status HSet field for error message
+	EndpointStatus_HSet_Error = "error"
+	// Endpoint status HSet field for last checked time in RFC3339, empty if never checked
 	EndpointStatus_HSet_LastChecked = "last_checked"
-	// Endpoint status HSet field for response time
+	// Endpoint status HSet field for response time in milliseconds
 	EndpointStatus_HSet_ResponseTime = "response_time"
+	// Endpoint status HSet field for state of last check result
+	EndpointStatus_HSet_CheckState = "check_state"
+	// Endpoint status HSet field for consecutive failed checks
+	EndpointStatus_HSet_Failures = "failures"
+	// Endpoint status HSet field for consecutive successful checks
+	EndpointStatus_HSet_Successes = "successes"
+	// Endpoint status HSet field for time endpoint entered its state in RFC3339, empty if never checked
+	EndpointStatus_HSet_StateSince = "state_since"
+	// Endpoint status HSet field for last confirmed state other than flapping
+	EndpointStatus_HSet_StableState = "stable_state"
+	// Endpoint status HSet field for time endpoint entered its stable state in RFC3339, empty if never checked
+	EndpointStatus_HSet_StableSince = "stable_since"
+	// Endpoint status HSet field for recent check results bitmask, newest in lowest bit
+	EndpointStatus_HSet_FlapRecent = "flap_recent"
+	// Endpoint status HSet field for amount of results in recent check results bitmask
+	EndpointStatus_HSet_FlapRecentCount = "flap_recent_count"
+	// Endpoint status HSet field for flap score percentage
+	EndpointStatus_HSet_FlapScore = "flap_score"
+	// Endpoint status HSet field for flapping flag, "1" or "0"
+	EndpointStatus_HSet_Flapping = "flapping"
+	// Endpoint status HSet field for websocket handshake time in milliseconds
+	EndpointStatus_HSet_HandshakeTime = "handshake_time"
+	// Endpoint status HSet field for websocket round-trip time in milliseconds
+	EndpointStatus_HSet_RoundTripTime = "round_trip_time"
+	// Endpoint status HSet field for certificate, JSON object. Empty if check did no tls handshake
+	EndpointStatus_HSet_Cert = "cert"
+	// Endpoint status HSet field for active incident id, empty if endpoint has none
+	EndpointStatus_HSet_IncidentID = "incident_id"
+
+	//* heartbeat
+
+	// Heartbeat token HSet field for project id
+	HeartbeatToken_HSet_ProjectID = "project_id"
+	// Heartbeat token HSet field for endpoint id
+	HeartbeatToken_HSet_EndpointID = "endpoint_id"
+	// Heartbeat state HSet field for time next heartbeat is due, unix ms
+	Heartbeat_HSet_DueAt = "due_at"
+	// Heartbeat state HSet field for current job run start time, unix ms. Zero if job is not running
+	Heartbeat_HSet_StartedAt = "started_at"
+
+	//* notification channel
+
+	// Channel info HSet field for name
+	Channel_HSet_Name = "name"
+	// Channel info HSet field for channel type
+	Channel_HSet_Type = "type"
+	// Channel info HSet field for enabled flag, "1" or "0"
+	Channel_HSet_Enabled = "enabled"
+	// Channel info HSet field for webhook url
+	Channel_HSet_WebhookURL = "webhook_url"
+	// Channel info HSet field for webhook signature secret
+	Channel_HSet_WebhookSecret = "webhook_secret"
+	// Channel info HSet field for smtp server host
+	Channel_HSet_SMTPHost = "smtp_host"
+	// Channel info HSet field for smtp server port
+	Channel_HSet_SMTPPort = "smtp_port"
+	// Channel info HSet field for smtp STARTTLS flag, "1" or "0"
+	Channel_HSet_SMTPStartTLS = "smtp_starttls"
+	// Channel info HSet field for smtp auth username
+	Channel_HSet_SMTPUsername = "smtp_username"
+	// Channel info HSet field for smtp auth password
+	Channel_HSet_SMTPPassword = "smtp_password"
+	// Channel info HSet field for sender address
+	Channel_HSet_SMTPFrom = "smtp_from"
+	// Channel info HSet field for recipient addresses, JSON array
+	Channel_HSet_SMTPRecipients = "smtp_recipients"
+	// Channel info HSet field for telegram bot api base url
+	Channel_HSet_TelegramAPIURL = "telegram_api_url"
+	// Channel info HSet field for telegram bot token
+	Channel_HSet_TelegramBotToken = "telegram_bot_token"
+	// Channel info HSet field for telegram chat id
+	Channel_HSet_TelegramChatID = "telegram_chat_id"
+
+	//* incident
+
+	// Incident info HSet field for endpoint id
+	Incident_HSet_EndpointID = "endpoint_id"
+	// Incident info HSet field for endpoint name
+	Incident_HSet_EndpointName = "endpoint_name"
+	// Incident info HSet field for incident state
+	Incident_HSet_State = "state"
+	// Incident info HSet field for failure reason that opened incident
+	Incident_HSet_Cause = "cause"
+	// Incident info HSet field for opening time, unix ms
+	Incident_HSet_OpenedAt = "opened_at"
+	// Incident info HSet field for acknowledge time, unix ms. Zero if not acknowledged
+	Incident_HSet_AcknowledgedAt = "acknowledged_at"
+	// Incident info HSet field for who acknowledged incident
+	Incident_HSet_AcknowledgedBy = "acknowledged_by"
+	// Incident info HSet field for resolve time, unix ms. Zero if not resolved
+	Incident_HSet_ResolvedAt = "resolved_at"
+	// Incident info HSet field for who resolved incident, empty if resolved automatically
+	Incident_HSet_ResolvedBy = "resolved_by"
+
+	//* escalation
+
+	// Escalation policy HSet field for name
+	EscalationPolicy_HSet_Name = "name"
+	// Escalation policy HSet field for steps, JSON array
+	EscalationPolicy_HSet_Steps = "steps"
+	// Pending escalation HSet field for policy id
+	Escalation_HSet_PolicyID = "policy_id"
+	// Pending escalation HSet field for index of next step
+	Escalation_HSet_Step = "step"
+	// Pending escalation HSet field for rounds of policy steps done
+	Escalation_HSet_Round = "round"
+	// Pending escalation HSet field for time next step is due, unix ms
+	Escalation_HSet_DueAt = "due_at"
+
+	//* on-call schedule
+
+	// Schedule info HSet field for name
+	Schedule_HSet_Name = "name"
+	// Schedule info HSet field for IANA time zone name
+	Schedule_HSet_Timezone = "timezone"
+	// Schedule info HSet field for rotation type
+	Schedule_HSet_Rotation = "rotation"
+	// Schedule info HSet field for handoff time in minutes since midnight
+	Schedule_HSet_HandoffTime = "handoff_time"
+	// Schedule info HSet field for handoff weekday, 0 is sunday
+	Schedule_HSet_HandoffWeekday = "handoff_weekday"
+	// Schedule info HSet field for rotation start, unix ms
+	Schedule_HSet_Start = "start"
+	// Schedule info HSet field for participants in rotation order, JSON array
+	Schedule_HSet_Participants = "participants"
 )
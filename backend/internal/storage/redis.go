@@ -6,6 +6,10 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/wrtgvr/websites-monitor/internal/config"
@@ -13,10 +17,18 @@ import (
 	errs "github.com/wrtgvr/websites-monitor/internal/errors"
 )
 
+// Amount of history records read per request by `ScanEndpointHistory()`
+const historyScanBatch = 1000
+
 type RedisStorage struct {
-	client          *redis.Client
-	maxEndpoints    int64
-	maxReadOnlyKeys int64
+	client           *redis.Client
+	maxEndpoints     int64
+	maxReadOnlyKeys  int64
+	maxChannels      int64
+	maxPolicies      int64
+	maxSchedules     int64
+	maxDeliveries    int64
+	historyRetention time.Duration
 }
 
 func NewRedisStorage(cfg *config.RedisConfig) *RedisStorage {
@@ -26,8 +38,13 @@ func NewRedisStorage(cfg *config.RedisConfig) *RedisStorage {
 			Password: cfg.Password,
 			DB:       cfg.DB,
 		}),
-		maxEndpoints:    cfg.MaxEndpoints,
-		maxReadOnlyKeys: cfg.MaxReadOnlyKeys,
+		maxEndpoints:     cfg.MaxEndpoints,
+		maxReadOnlyKeys:  cfg.MaxReadOnlyKeys,
+		maxChannels:      cfg.MaxChannels,
+		maxPolicies:      cfg.MaxEscalationPolicies,
+		maxSchedules:     cfg.MaxSchedules,
+		maxDeliveries:    cfg.MaxDeliveries,
+		historyRetention: cfg.HistoryRetention,
 	}
 }
 
@@ -62,6 +79,44 @@ func (s *RedisStorage) CreateProject(ctx context.Context, projectInfo *domain.Pr
 	return nil
 }
 
+func (s *RedisStorage) GetProjectIDs(ctx context.Context) ([]string, *errs.AppError) {
+	ids, err := s.client.ZRange(ctx, s.key_Projects(), 0, -1).Result()
+	if err != nil {
+		return nil, errs.NewInternalError(
+			fmt.Errorf("failed to get project ids, err=%w", err))
+	}
+	return ids, nil
+}
+
+func (s *RedisStorage) BackfillProjectIDs(ctx context.Context) *errs.AppError {
+	var added int64
+	iter := s.client.ScanType(ctx, 0, s.key_ProjectInfo("*"), 100, "hash").Iterator()
+	for iter.Next(ctx) {
+		// info keys of projects have no other parts after id
+		projectId := strings.TrimPrefix(iter.Val(), s.key_ProjectInfo(""))
+		if projectId == "" || strings.Contains(projectId, ":") {
+			continue
+		}
+		n, err := s.client.ZAddNX(ctx, s.key_Projects(), redis.Z{
+			Score:  float64(time.Now().Unix()),
+			Member: projectId,
+		}).Result()
+		if err != nil {
+			return errs.NewInternalError(
+				fmt.Errorf("failed to add project id: project_id=%s, err=%w", projectId, err))
+		}
+		added += n
+	}
+	if err := iter.Err(); err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to scan projects, err=%w", err))
+	}
+	if added > 0 {
+		log.Printf("WARN: project list was missing %d projects, added them\n", added)
+	}
+	return nil
+}
+
 func (s *RedisStorage) GetProjectIDByAPIKey(ctx context.Context, apiKey string) (string, *errs.AppError) {
 	id, err := s.client.HGet(ctx, s.key_ProjectByAdminAPIKey(apiKey), AdminAPIKey_HSet_ProjectID).Result()
 	if err != nil {
@@ -153,6 +208,7 @@ func (s *RedisStorage) DeleteProject(ctx context.Context, projectId string) *err
 		s.key_ProjectInfo(projectId),
 		s.key_ProjectByAdminAPIKey(adminKey),
 		s.key_ProjectAPIKeys(projectId))
+	pipe.ZRem(ctx, s.key_Projects(), projectId)
 
 	_, err = pipe.Exec(ctx)
 	if err != nil {
@@ -330,7 +386,7 @@ func (s *RedisStorage) CreateEndpoint(ctx context.Context, endpointInfo *domain.
 
 	_, err = pipe.Exec(ctx)
 	if err != nil {
-		errs.NewInternalError(fmt.Errorf("failed to create new endpoint: project_id=%s, err=%w", endpointInfo.ProjectId, err))
+		return errs.NewInternalError(fmt.Errorf("failed to create new endpoint: project_id=%s, err=%w", endpointInfo.ProjectId, err))
 	}
 
 	return nil
@@ -338,10 +394,46 @@ func (s *RedisStorage) CreateEndpoint(ctx context.Context, endpointInfo *domain.
 
 func (s *RedisStorage) GetEndpoints(ctx context.Context, projectId, endpointId string) ([]*domain.Endpoint, *errs.AppError) {
 	//* get ids of endpoints
-	ids, err := s.client.ZRange(ctx, s.key_ProjectEndpoints(projectId), 0, -1).Result()
+	var ids []string
+	if endpointId != "" {
+		// only given endpoint
+		err := s.client.ZScore(ctx, s.key_ProjectEndpoints(projectId), endpointId).Err()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				return nil, errs.NewNotFound(err,
+					fmt.Sprintf("endpoint not found: id=%s", endpointId))
+			}
+			return nil, errs.NewInternalError(err)
+		}
+		ids = []string{endpointId}
+	} else {
+		var err error
+		ids, err = s.client.ZRange(ctx, s.key_ProjectEndpoints(projectId), 0, -1).Result()
+		if err != nil {
+			return nil, errs.NewInternalError(err)
+		}
+	}
+	return s.loadEndpoints(ctx, projectId, ids)
+}
+
+func (s *RedisStorage) GetEndpointsPage(ctx context.Context, projectId string, limit, offset int64) ([]*domain.Endpoint, *errs.AppError) {
+	stop := offset + limit - 1
+	if stop < offset {
+		// overflow, offset is past any end anyway
+		stop = -1
+	}
+	ids, err := s.client.ZRange(ctx, s.key_ProjectEndpoints(projectId), offset, stop).Result()
 	if err != nil {
 		return nil, errs.NewInternalError(err)
 	}
+	return s.loadEndpoints(ctx, projectId, ids)
+}
+
+// Load info and status of endpoints with given ids
+func (s *RedisStorage) loadEndpoints(ctx context.Context, projectId string, ids []string) ([]*domain.Endpoint, *errs.AppError) {
+	if len(ids) == 0 {
+		return []*domain.Endpoint{}, nil
+	}
 
 	//* prepare pipeline for info and status and execute cmds
 	pipe := s.client.Pipeline()
@@ -369,7 +461,7 @@ func (s *RedisStorage) GetEndpoints(ctx context.Context, projectId, endpointId s
 		}
 
 		// check for required field
-		if info[EndpointInfo_HSet_Url] == "" {
+		if info[EndpointInfo_HSet_Url] == "" && info[EndpointInfo_HSet_Type] != string(domain.CheckTypeHeartbeat) {
 			log.Printf("WARN: endpoint does not have required URL field, id=%s, err=%v\n", id, err)
 			failedEndpoints++
 			continue
@@ -380,14 +472,14 @@ func (s *RedisStorage) GetEndpoints(ctx context.Context, projectId, endpointId s
 			return nil, errs.NewInternalError(fmt.Errorf("failed to get endpoint status cmd result, err=%w", err))
 		}
 
+		result, lastChecked := checkResultFromHash(status)
 		endpoints = append(endpoints, &domain.Endpoint{
-			ID:           id,
-			Name:         info[EndpointInfo_HSet_Name],
-			URL:          info[EndpointInfo_HSet_Url],
-			ProjectId:    info[EndpointInfo_HSet_ProjectId],
-			Status:       status[EndpointStatus_HSet_Status],
-			LastChecked:  status[EndpointStatus_HSet_LastChecked],
-			ResponseTime: status[EndpointStatus_HSet_ResponseTime],
+			EndpointInfo:  *endpointInfoFromHash(id, info),
+			LastChecked:   lastChecked,
+			CheckResult:   result,
+			Confirmation:  confirmationFromHash(status),
+			FlapDetection: flapDetectionFromHash(status),
+			IncidentID:    status[EndpointStatus_HSet_IncidentID],
 		})
 	}
 
@@ -428,17 +520,12 @@ func (s *RedisStorage) GetEndpointsForMonitoring(ctx context.Context, projectId
 			continue
 		}
 		// check for required field
-		if info[EndpointInfo_HSet_Url] == "" {
+		if info[EndpointInfo_HSet_Url] == "" && info[EndpointInfo_HSet_Type] != string(domain.CheckTypeHeartbeat) {
 			log.Printf("WARN: endpoint does not have required URL field, id=%s, err=%v\n", id, err)
 			failedEndpoints++
 			continue
 		}
-		endpoints = append(endpoints, &domain.EndpointInfo{
-			ID:        id,
-			Name:      info[EndpointInfo_HSet_Name],
-			URL:       info[EndpointInfo_HSet_Url],
-			ProjectId: info[EndpointInfo_HSet_ProjectId],
-		})
+		endpoints = append(endpoints, endpointInfoFromHash(id, info))
 	}
 
 	if failedEndpoints > 0 {
@@ -449,33 +536,23 @@ func (s *RedisStorage) GetEndpointsForMonitoring(ctx context.Context, projectId
 }
 
 func (s *RedisStorage) UpdateEndpointInfo(ctx context.Context, ep *domain.EndpointInfo) *errs.AppError {
-	//* get current url and name
-	res, err := s.client.HGetAll(ctx, s.key_EndpointInfo(ep.ProjectId, ep.ID)).Result()
+	//* check if endpoint exists
+	n, err := s.client.Exists(ctx, s.key_EndpointInfo(ep.ProjectId, ep.ID)).Result()
 	if err != nil {
-		if errors.Is(err, redis.Nil) {
-			return errs.NewNotFound(err,
-				fmt.Sprintf("endpoint not found: id=%s", ep.ID))
-		}
 		return errs.NewInternalError(
 			fmt.Errorf("failed to get endpoint info: id=%s, err=%w", ep.ID, err))
 	}
-
-	//* if url or name from given info struct is nil value ("") then use enpodint current url/name
-	url := ep.URL
-	if url == "" {
-		url = res[EndpointInfo_HSet_Url]
-	}
-	name := ep.Name
-	if name == "" {
-		name = res[EndpointInfo_HSet_Name]
+	if n == 0 {
+		return errs.NewNotFound(nil,
+			fmt.Sprintf("endpoint not found: id=%s", ep.ID))
 	}
 
-	//* update endpoint
-	err = s.client.HSet(ctx, s.key_EndpointInfo(ep.ProjectId, ep.ID),
-		EndpointInfo_HSet_Url, url,
-		EndpointInfo_HSet_Name, name,
-	).Err()
-	if err != nil {
+	//* update endpoint, every field is overwritten
+	pipe := s.client.TxPipeline()
+	pipe.HSet(ctx, s.key_EndpointInfo(ep.ProjectId, ep.ID), endpointInfoToHash(ep)...)
+	s.setHeartbeatToken_AddToPipe(ctx, pipe, ep)
+
+	if _, err = pipe.Exec(ctx); err != nil {
 		return errs.NewInternalError(
 			fmt.Errorf("failed to update endpoint info: req_endpoint_id=%s, req_new_endpoint_name=%s, req_new_endpoint_url=%s, err=%w", ep.ID, ep.Name, ep.URL, err))
 	}
@@ -483,6 +560,29 @@ func (s *RedisStorage) UpdateEndpointInfo(ctx context.Context, ep *domain.Endpoi
 	return nil
 }
 
+func (s *RedisStorage) GetEndpointStatus(ctx context.Context, projectId, endpointId string) (*domain.EndpointStatus, *errs.AppError) {
+	status, err := s.client.HGetAll(ctx, s.key_EndpointStatus(projectId, endpointId)).Result()
+	if err != nil {
+		return nil, errs.NewInternalError(
+			fmt.Errorf("failed to get endpoint status: id=%s, err=%w", endpointId, err))
+	}
+	if len(status) == 0 {
+		return nil, errs.NewNotFound(nil,
+			fmt.Sprintf("endpoint not found: id=%s", endpointId))
+	}
+
+	result, lastChecked := checkResultFromHash(status)
+	return &domain.EndpointStatus{
+		ID:            endpointId,
+		ProjectId:     projectId,
+		LastChecked:   lastChecked,
+		CheckResult:   result,
+		Confirmation:  confirmationFromHash(status),
+		FlapDetection: flapDetectionFromHash(status),
+		IncidentID:    status[EndpointStatus_HSet_IncidentID],
+	}, nil
+}
+
 func (s *RedisStorage) UpdateEndpointStatus(ctx context.Context, projectId string, ep *domain.EndpointStatus) *errs.AppError {
 	//* check if endpoint exists
 	n, err := s.client.Exists(ctx, s.key_EndpointInfo(projectId, ep.ID)).Result()
@@ -497,11 +597,7 @@ func (s *RedisStorage) UpdateEndpointStatus(ctx context.Context, projectId strin
 	}
 
 	//* update endpoint status
-	err = s.client.HSet(ctx, s.key_EndpointInfo(projectId, ep.ID),
-		EndpointStatus_HSet_Status, ep.Status,
-		EndpointStatus_HSet_LastChecked, ep.LastChecked,
-		EndpointStatus_HSet_ResponseTime, ep.ResponseTime,
-	).Err()
+	err = s.client.HSet(ctx, s.key_EndpointStatus(projectId, ep.ID), endpointStatusToHash(ep)...).Err()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
 			return errs.NewAppError(err, fmt.Sprintf("endpoint with not found id=%s", ep.ID), errs.TypeNotFound, http.StatusNotFound)
@@ -524,10 +620,56 @@ func (s *RedisStorage) DeleteEndpoint(ctx context.Context, projectId, endpointId
 			fmt.Sprintf("project not found: id=%s", projectId))
 	}
 
+	// heartbeat token is stored outside of endpoint keys
+	token, err := s.client.HGet(ctx, s.key_EndpointInfo(projectId, endpointId), EndpointInfo_HSet_HeartbeatToken).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to get endpoint heartbeat token: endpoint_id=%s, err=%w", endpointId, err))
+	}
+
+	// incident of endpoint is resolved, so it is not escalated after endpoint is gone
+	var inc *domain.Incident
+	incidentId, err := s.client.HGet(ctx, s.key_EndpointStatus(projectId, endpointId), EndpointStatus_HSet_IncidentID).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to get endpoint incident: endpoint_id=%s, err=%w", endpointId, err))
+	}
+	if incidentId != "" {
+		info, err := s.client.HGetAll(ctx, s.key_IncidentInfo(projectId, incidentId)).Result()
+		if err != nil {
+			return errs.NewInternalError(
+				fmt.Errorf("failed to get incident: id=%s, err=%w", incidentId, err))
+		}
+		if len(info) > 0 {
+			inc = incidentFromHash(projectId, incidentId, info)
+		}
+	}
+
 	//* prepare pipeline
 	pipe := s.client.TxPipeline()
 
 	s.deleteEndpoint_AddToPipe(ctx, pipe, endpointId, projectId)
+	if token != "" {
+		pipe.Del(ctx, s.key_HeartbeatToken(token))
+	}
+	if incidentId != "" {
+		s.deleteEscalation_AddToPipe(ctx, pipe, projectId, incidentId)
+	}
+	if inc != nil && inc.Resolve(time.Now(), "") == nil {
+		pipe.HSet(ctx, s.key_IncidentInfo(projectId, incidentId), incidentToHash(inc)...)
+		member, err := encodeTimelineRecord(&domain.TimelineEntry{
+			At:      inc.ResolvedAt,
+			Type:    domain.TimelineState,
+			State:   string(domain.IncidentResolved),
+			Message: "endpoint was deleted",
+		})
+		if err == nil {
+			pipe.ZAdd(ctx, s.key_IncidentTimeline(projectId, incidentId), redis.Z{
+				Score:  float64(inc.ResolvedAt.UnixMilli()),
+				Member: member,
+			})
+		}
+	}
 
 	//* execute
 	_, err = pipe.Exec(ctx)
@@ -537,3 +679,1086 @@ func (s *RedisStorage) DeleteEndpoint(ctx context.Context, projectId, endpointId
 
 	return nil
 }
+
+//* heartbeat
+
+func (s *RedisStorage) GetEndpointByHeartbeatToken(ctx context.Context, token string) (*domain.EndpointInfo, *errs.AppError) {
+	ids, err := s.client.HGetAll(ctx, s.key_HeartbeatToken(token)).Result()
+	if err != nil {
+		return nil, errs.NewInternalError(
+			fmt.Errorf("failed to get heartbeat token, err=%w", err))
+	}
+	if len(ids) == 0 {
+		return nil, errs.NewNotFound(nil, "heartbeat not found")
+	}
+
+	projectId, endpointId := ids[HeartbeatToken_HSet_ProjectID], ids[HeartbeatToken_HSet_EndpointID]
+	info, err := s.client.HGetAll(ctx, s.key_EndpointInfo(projectId, endpointId)).Result()
+	if err != nil {
+		return nil, errs.NewInternalError(
+			fmt.Errorf("failed to get endpoint info: id=%s, err=%w", endpointId, err))
+	}
+	// token is left from deleted endpoint or endpoint got new token
+	if len(info) == 0 || info[EndpointInfo_HSet_HeartbeatToken] != token {
+		return nil, errs.NewNotFound(nil, "heartbeat not found")
+	}
+
+	return endpointInfoFromHash(endpointId, info), nil
+}
+
+func (s *RedisStorage) GetHeartbeatState(ctx context.Context, projectId, endpointId string) (*domain.HeartbeatState, *errs.AppError) {
+	state, err := s.client.HGetAll(ctx, s.key_EndpointHeartbeat(projectId, endpointId)).Result()
+	if err != nil {
+		return nil, errs.NewInternalError(
+			fmt.Errorf("failed to get heartbeat state: endpoint_id=%s, err=%w", endpointId, err))
+	}
+
+	return &domain.HeartbeatState{
+		DueAt:     parseUnixMilli(state[Heartbeat_HSet_DueAt]),
+		StartedAt: parseUnixMilli(state[Heartbeat_HSet_StartedAt]),
+	}, nil
+}
+
+func (s *RedisStorage) UpdateHeartbeatState(ctx context.Context, projectId, endpointId string, state *domain.HeartbeatState) *errs.AppError {
+	err := s.client.HSet(ctx, s.key_EndpointHeartbeat(projectId, endpointId),
+		Heartbeat_HSet_DueAt, formatUnixMilli(state.DueAt),
+		Heartbeat_HSet_StartedAt, formatUnixMilli(state.StartedAt)).Err()
+	if err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to update heartbeat state: endpoint_id=%s, err=%w", endpointId, err))
+	}
+
+	return nil
+}
+
+//* history
+
+func (s *RedisStorage) AddEndpointCheck(ctx context.Context, projectId string, check *domain.EndpointCheck) *errs.AppError {
+	member, err := encodeHistoryRecord(check)
+	if err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to encode endpoint check: endpoint_id=%s, err=%w", check.EndpointID, err))
+	}
+
+	key := s.key_EndpointHistory(projectId, check.EndpointID)
+
+	//* prepare pipeline
+	pipe := s.client.TxPipeline()
+
+	pipe.ZAdd(ctx, key, redis.Z{
+		Score:  float64(check.CheckedAt.UnixMilli()),
+		Member: member,
+	})
+	// drop records older than retention period
+	pipe.ZRemRangeByScore(ctx, key, "-inf",
+		fmt.Sprintf("(%d", check.CheckedAt.Add(-s.historyRetention).UnixMilli()))
+
+	//* execute
+	if _, err := pipe.Exec(ctx); err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to add endpoint check: project_id=%s, endpoint_id=%s, err=%w", projectId, check.EndpointID, err))
+	}
+	return nil
+}
+
+func (s *RedisStorage) GetEndpointHistory(ctx context.Context, projectId, endpointId string, from, to time.Time, limit, offset int64) ([]*domain.EndpointCheck, int64, *errs.AppError) {
+	//* check if endpoint exists
+	n, err := s.client.Exists(ctx, s.key_EndpointInfo(projectId, endpointId)).Result()
+	if err != nil {
+		return nil, 0, errs.NewInternalError(
+			fmt.Errorf("failed to get endpoint info: id=%s, err=%w", endpointId, err))
+	}
+	if n == 0 {
+		return nil, 0, errs.NewNotFound(nil,
+			fmt.Sprintf("endpoint not found: id=%s", endpointId))
+	}
+
+	key := s.key_EndpointHistory(projectId, endpointId)
+	minScore := strconv.FormatInt(from.UnixMilli(), 10)
+	maxScore := strconv.FormatInt(to.UnixMilli(), 10)
+
+	//* prepare pipeline
+	pipe := s.client.Pipeline()
+
+	totalCmd := pipe.ZCount(ctx, key, minScore, maxScore)
+	membersCmd := pipe.ZRangeArgs(ctx, redis.ZRangeArgs{
+		Key:     key,
+		Start:   minScore,
+		Stop:    maxScore,
+		ByScore: true,
+		Offset:  offset,
+		Count:   limit,
+	})
+
+	//* execute
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, 0, errs.NewInternalError(
+			fmt.Errorf("failed to get endpoint history: project_id=%s, endpoint_id=%s, err=%w", projectId, endpointId, err))
+	}
+
+	//* get result
+	checks := make([]*domain.EndpointCheck, 0, len(membersCmd.Val()))
+	for _, member := range membersCmd.Val() {
+		check, err := decodeHistoryRecord(endpointId, member)
+		if err != nil {
+			log.Printf("WARN: failed to decode history record, endpoint_id=%s, err=%v\n", endpointId, err)
+			continue
+		}
+		checks = append(checks, check)
+	}
+
+	return checks, totalCmd.Val(), nil
+}
+
+func (s *RedisStorage) ScanEndpointHistory(ctx context.Context, projectId, endpointId string, from, to time.Time, fn func(check *domain.EndpointCheck)) *errs.AppError {
+	//* check if endpoint exists
+	n, err := s.client.Exists(ctx, s.key_EndpointInfo(projectId, endpointId)).Result()
+	if err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to get endpoint info: id=%s, err=%w", endpointId, err))
+	}
+	if n == 0 {
+		return errs.NewNotFound(nil,
+			fmt.Sprintf("endpoint not found: id=%s", endpointId))
+	}
+
+	key := s.key_EndpointHistory(projectId, endpointId)
+	maxScore := strconv.FormatInt(to.UnixMilli(), 10)
+
+	// batches are paged by score, records with the same score as cursor were already read
+	cursor := from.UnixMilli()
+	var skip int64
+	for {
+		members, err := s.client.ZRangeArgsWithScores(ctx, redis.ZRangeArgs{
+			Key:     key,
+			Start:   strconv.FormatInt(cursor, 10),
+			Stop:    maxScore,
+			ByScore: true,
+			Offset:  skip,
+			Count:   historyScanBatch,
+		}).Result()
+		if err != nil {
+			return errs.NewInternalError(
+				fmt.Errorf("failed to get endpoint history: project_id=%s, endpoint_id=%s, err=%w", projectId, endpointId, err))
+		}
+
+		for _, z := range members {
+			check, err := decodeHistoryRecord(endpointId, z.Member.(string))
+			if err != nil {
+				log.Printf("WARN: failed to decode history record, endpoint_id=%s, err=%v\n", endpointId, err)
+				continue
+			}
+			fn(check)
+		}
+		if len(members) < historyScanBatch {
+			return nil
+		}
+
+		//* move cursor to last score of batch
+		last := int64(members[len(members)-1].Score)
+		if last != cursor {
+			cursor, skip = last, 0
+		}
+		for i := len(members) - 1; i >= 0 && int64(members[i].Score) == cursor; i-- {
+			skip++
+		}
+	}
+}
+
+//* notification channels
+
+func (s *RedisStorage) CreateNotificationChannel(ctx context.Context, ch *domain.NotificationChannel) *errs.AppError {
+	//* check if project exists
+	n, err := s.client.Exists(ctx, s.key_ProjectInfo(ch.ProjectId)).Result()
+	if err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to get project: project_id=%s, err=%w", ch.ProjectId, err))
+	}
+	if n == 0 {
+		return errs.NewNotFound(nil,
+			fmt.Sprintf("project not found: id=%s", ch.ProjectId))
+	}
+
+	//* check amount of channels
+	n, err = s.client.ZCard(ctx, s.key_ProjectChannels(ch.ProjectId)).Result()
+	if err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to get amount of zset members: project_id=%s, err=%w", ch.ProjectId, err))
+	}
+	if n >= s.maxChannels {
+		return errs.NewConflict(nil, fmt.Sprintf("A project cannot have more than %d notification channels", s.maxChannels))
+	}
+
+	//* prepare pipeline
+	pipe := s.client.TxPipeline()
+
+	pipe.ZAdd(ctx, s.key_ProjectChannels(ch.ProjectId), redis.Z{
+		Score:  float64(time.Now().Unix()),
+		Member: ch.ID,
+	})
+	pipe.HSet(ctx, s.key_ChannelInfo(ch.ProjectId, ch.ID), channelToHash(ch)...)
+
+	//* execute
+	if _, err := pipe.Exec(ctx); err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to create notification channel: project_id=%s, err=%w", ch.ProjectId, err))
+	}
+	return nil
+}
+
+func (s *RedisStorage) GetNotificationChannels(ctx context.Context, projectId string) ([]*domain.NotificationChannel, *errs.AppError) {
+	ids, err := s.client.ZRange(ctx, s.key_ProjectChannels(projectId), 0, -1).Result()
+	if err != nil {
+		return nil, errs.NewInternalError(
+			fmt.Errorf("failed to get notification channels: project_id=%s, err=%w", projectId, err))
+	}
+
+	//* prepare pipeline
+	pipe := s.client.Pipeline()
+
+	infoCmds := make(map[string]*redis.MapStringStringCmd)
+	for _, id := range ids {
+		infoCmds[id] = pipe.HGetAll(ctx, s.key_ChannelInfo(projectId, id))
+	}
+
+	//* execute
+	if len(ids) > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, errs.NewInternalError(fmt.Errorf("pipe execution failed: %w", err))
+		}
+	}
+
+	//* get result
+	channels := make([]*domain.NotificationChannel, 0, len(ids))
+	for _, id := range ids {
+		info := infoCmds[id].Val()
+		if len(info) == 0 {
+			log.Printf("WARN: notification channel info not found, project_id=%s, channel_id=%s\n", projectId, id)
+			continue
+		}
+		channels = append(channels, channelFromHash(projectId, id, info))
+	}
+	return channels, nil
+}
+
+func (s *RedisStorage) GetNotificationChannel(ctx context.Context, projectId, channelId string) (*domain.NotificationChannel, *errs.AppError) {
+	info, err := s.client.HGetAll(ctx, s.key_ChannelInfo(projectId, channelId)).Result()
+	if err != nil {
+		return nil, errs.NewInternalError(
+			fmt.Errorf("failed to get notification channel: id=%s, err=%w", channelId, err))
+	}
+	if len(info) == 0 {
+		return nil, errs.NewNotFound(nil,
+			fmt.Sprintf("notification channel not found: id=%s", channelId))
+	}
+	return channelFromHash(projectId, channelId, info), nil
+}
+
+func (s *RedisStorage) UpdateNotificationChannel(ctx context.Context, ch *domain.NotificationChannel) *errs.AppError {
+	//* check if channel exists
+	n, err := s.client.Exists(ctx, s.key_ChannelInfo(ch.ProjectId, ch.ID)).Result()
+	if err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to get notification channel: id=%s, err=%w", ch.ID, err))
+	}
+	if n == 0 {
+		return errs.NewNotFound(nil,
+			fmt.Sprintf("notification channel not found: id=%s", ch.ID))
+	}
+
+	//* update channel, every field is overwritten
+	if err := s.client.HSet(ctx, s.key_ChannelInfo(ch.ProjectId, ch.ID), channelToHash(ch)...).Err(); err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to update notification channel: id=%s, err=%w", ch.ID, err))
+	}
+	return nil
+}
+
+func (s *RedisStorage) DeleteNotificationChannel(ctx context.Context, projectId, channelId string) *errs.AppError {
+	//* check if channel exists
+	n, err := s.client.Exists(ctx, s.key_ChannelInfo(projectId, channelId)).Result()
+	if err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to get notification channel: id=%s, err=%w", channelId, err))
+	}
+	if n == 0 {
+		return errs.NewNotFound(nil,
+			fmt.Sprintf("notification channel not found: id=%s", channelId))
+	}
+
+	//* prepare pipeline
+	pipe := s.client.TxPipeline()
+
+	s.deleteChannel_AddToPipe(ctx, pipe, projectId, channelId)
+
+	//* execute
+	if _, err := pipe.Exec(ctx); err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to delete notification channel: project_id=%s, channel_id=%s, err=%w", projectId, channelId, err))
+	}
+	return nil
+}
+
+func (s *RedisStorage) AddNotificationDelivery(ctx context.Context, projectId string, d *domain.Delivery) *errs.AppError {
+	member, err := encodeDeliveryRecord(d)
+	if err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to encode delivery: channel_id=%s, err=%w", d.ChannelID, err))
+	}
+
+	key := s.key_ChannelDeliveries(projectId, d.ChannelID)
+
+	//* prepare pipeline
+	pipe := s.client.TxPipeline()
+
+	pipe.ZAdd(ctx, key, redis.Z{
+		Score:  float64(d.At.UnixMilli()),
+		Member: member,
+	})
+	// keep only last deliveries
+	pipe.ZRemRangeByRank(ctx, key, 0, -s.maxDeliveries-1)
+
+	//* execute
+	if _, err := pipe.Exec(ctx); err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to add delivery: project_id=%s, channel_id=%s, err=%w", projectId, d.ChannelID, err))
+	}
+	return nil
+}
+
+func (s *RedisStorage) GetNotificationDeliveries(ctx context.Context, projectId, channelId string, limit, offset int64) ([]*domain.Delivery, int64, *errs.AppError) {
+	//* check if channel exists
+	n, err := s.client.Exists(ctx, s.key_ChannelInfo(projectId, channelId)).Result()
+	if err != nil {
+		return nil, 0, errs.NewInternalError(
+			fmt.Errorf("failed to get notification channel: id=%s, err=%w", channelId, err))
+	}
+	if n == 0 {
+		return nil, 0, errs.NewNotFound(nil,
+			fmt.Sprintf("notification channel not found: id=%s", channelId))
+	}
+
+	key := s.key_ChannelDeliveries(projectId, channelId)
+
+	//* prepare pipeline
+	pipe := s.client.Pipeline()
+
+	totalCmd := pipe.ZCard(ctx, key)
+	membersCmd := pipe.ZRevRange(ctx, key, offset, offset+limit-1)
+
+	//* execute
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, 0, errs.NewInternalError(
+			fmt.Errorf("failed to get deliveries: project_id=%s, channel_id=%s, err=%w", projectId, channelId, err))
+	}
+
+	//* get result
+	deliveries := make([]*domain.Delivery, 0, len(membersCmd.Val()))
+	for _, member := range membersCmd.Val() {
+		d, err := decodeDeliveryRecord(channelId, member)
+		if err != nil {
+			log.Printf("WARN: failed to decode delivery record, channel_id=%s, err=%v\n", channelId, err)
+			continue
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, totalCmd.Val(), nil
+}
+
+//* incidents
+
+// Max attempts of optimistic incident update
+const maxIncidentUpdateAttempts = 5
+
+func (s *RedisStorage) CreateIncident(ctx context.Context, inc *domain.Incident, timeline []*domain.TimelineEntry) *errs.AppError {
+	members := make([]redis.Z, 0, len(timeline))
+	for _, e := range timeline {
+		member, err := encodeTimelineRecord(e)
+		if err != nil {
+			return errs.NewInternalError(
+				fmt.Errorf("failed to encode timeline entry: incident_id=%s, err=%w", inc.ID, err))
+		}
+		members = append(members, redis.Z{
+			Score:  float64(e.At.UnixMilli()),
+			Member: member,
+		})
+	}
+
+	//* prepare pipeline
+	pipe := s.client.TxPipeline()
+
+	pipe.ZAdd(ctx, s.key_ProjectIncidents(inc.ProjectId), redis.Z{
+		Score:  float64(inc.OpenedAt.UnixMilli()),
+		Member: inc.ID,
+	})
+	pipe.HSet(ctx, s.key_IncidentInfo(inc.ProjectId, inc.ID), incidentToHash(inc)...)
+	if len(members) > 0 {
+		pipe.ZAdd(ctx, s.key_IncidentTimeline(inc.ProjectId, inc.ID), members...)
+	}
+
+	//* execute
+	if _, err := pipe.Exec(ctx); err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to create incident: project_id=%s, endpoint_id=%s, err=%w", inc.ProjectId, inc.EndpointID, err))
+	}
+	return nil
+}
+
+func (s *RedisStorage) GetIncident(ctx context.Context, projectId, incidentId string) (*domain.Incident, *errs.AppError) {
+	info, err := s.client.HGetAll(ctx, s.key_IncidentInfo(projectId, incidentId)).Result()
+	if err != nil {
+		return nil, errs.NewInternalError(
+			fmt.Errorf("failed to get incident: id=%s, err=%w", incidentId, err))
+	}
+	if len(info) == 0 {
+		return nil, errs.NewNotFound(nil,
+			fmt.Sprintf("incident not found: id=%s", incidentId))
+	}
+	return incidentFromHash(projectId, incidentId, info), nil
+}
+
+func (s *RedisStorage) GetIncidents(ctx context.Context, projectId string, filter *domain.IncidentFilter, from, to time.Time, limit, offset int64) ([]*domain.Incident, int64, *errs.AppError) {
+	//* get ids of incidents opened in time range, newest first
+	ids, err := s.client.ZRevRangeByScore(ctx, s.key_ProjectIncidents(projectId), &redis.ZRangeBy{
+		Min: strconv.FormatInt(from.UnixMilli(), 10),
+		Max: strconv.FormatInt(to.UnixMilli(), 10),
+	}).Result()
+	if err != nil {
+		return nil, 0, errs.NewInternalError(
+			fmt.Errorf("failed to get incidents: project_id=%s, err=%w", projectId, err))
+	}
+
+	//* prepare pipeline
+	pipe := s.client.Pipeline()
+
+	infoCmds := make(map[string]*redis.MapStringStringCmd)
+	for _, id := range ids {
+		infoCmds[id] = pipe.HGetAll(ctx, s.key_IncidentInfo(projectId, id))
+	}
+
+	//* execute
+	if len(ids) > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, 0, errs.NewInternalError(fmt.Errorf("pipe execution failed: %w", err))
+		}
+	}
+
+	//* filter and paginate
+	var total int64
+	incidents := make([]*domain.Incident, 0, min(int64(len(ids)), limit))
+	for _, id := range ids {
+		info := infoCmds[id].Val()
+		if len(info) == 0 {
+			log.Printf("WARN: incident info not found, project_id=%s, incident_id=%s\n", projectId, id)
+			continue
+		}
+		inc := incidentFromHash(projectId, id, info)
+		if !filter.Match(inc) {
+			continue
+		}
+		if total >= offset && total < offset+limit {
+			incidents = append(incidents, inc)
+		}
+		total++
+	}
+
+	return incidents, total, nil
+}
+
+func (s *RedisStorage) UpdateIncident(ctx context.Context, projectId, incidentId string, update func(inc *domain.Incident) error) (*domain.Incident, *errs.AppError) {
+	key := s.key_IncidentInfo(projectId, incidentId)
+
+	var inc *domain.Incident
+	var appErr *errs.AppError
+	// transaction fails if incident was changed after it was read
+	txf := func(tx *redis.Tx) error {
+		info, err := tx.HGetAll(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		if len(info) == 0 {
+			appErr = errs.NewNotFound(nil, fmt.Sprintf("incident not found: id=%s", incidentId))
+			return nil
+		}
+
+		inc = incidentFromHash(projectId, incidentId, info)
+		if err := update(inc); err != nil {
+			appErr = errs.NewConflict(err, err.Error())
+			return nil
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, key, incidentToHash(inc)...)
+			// only open incidents are escalated
+			if inc.State != domain.IncidentOpen {
+				s.deleteEscalation_AddToPipe(ctx, pipe, projectId, incidentId)
+			}
+			return nil
+		})
+		return err
+	}
+
+	for range maxIncidentUpdateAttempts {
+		err := s.client.Watch(ctx, txf, key)
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		if err != nil {
+			return nil, errs.NewInternalError(
+				fmt.Errorf("failed to update incident: id=%s, err=%w", incidentId, err))
+		}
+		if appErr != nil {
+			return nil, appErr
+		}
+		return inc, nil
+	}
+	return nil, errs.NewInternalError(
+		fmt.Errorf("failed to update incident, too many concurrent updates: id=%s", incidentId))
+}
+
+func (s *RedisStorage) AddIncidentTimelineEntry(ctx context.Context, projectId, incidentId string, entry *domain.TimelineEntry) *errs.AppError {
+	//* check if incident exists
+	n, err := s.client.Exists(ctx, s.key_IncidentInfo(projectId, incidentId)).Result()
+	if err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to get incident: id=%s, err=%w", incidentId, err))
+	}
+	if n == 0 {
+		return errs.NewNotFound(nil,
+			fmt.Sprintf("incident not found: id=%s", incidentId))
+	}
+
+	member, err := encodeTimelineRecord(entry)
+	if err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to encode timeline entry: incident_id=%s, err=%w", incidentId, err))
+	}
+
+	err = s.client.ZAdd(ctx, s.key_IncidentTimeline(projectId, incidentId), redis.Z{
+		Score:  float64(entry.At.UnixMilli()),
+		Member: member,
+	}).Err()
+	if err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to add timeline entry: project_id=%s, incident_id=%s, err=%w", projectId, incidentId, err))
+	}
+	return nil
+}
+
+func (s *RedisStorage) GetIncidentTimeline(ctx context.Context, projectId, incidentId string) ([]*domain.TimelineEntry, *errs.AppError) {
+	members, err := s.client.ZRange(ctx, s.key_IncidentTimeline(projectId, incidentId), 0, -1).Result()
+	if err != nil {
+		return nil, errs.NewInternalError(
+			fmt.Errorf("failed to get incident timeline: project_id=%s, incident_id=%s, err=%w", projectId, incidentId, err))
+	}
+
+	timeline := make([]*domain.TimelineEntry, 0, len(members))
+	for _, member := range members {
+		e, err := decodeTimelineRecord(member)
+		if err != nil {
+			log.Printf("WARN: failed to decode timeline record, incident_id=%s, err=%v\n", incidentId, err)
+			continue
+		}
+		timeline = append(timeline, e)
+	}
+	// entries added within same millisecond are ordered by member, not by time
+	slices.SortStableFunc(timeline, func(a, b *domain.TimelineEntry) int {
+		return a.At.Compare(b.At)
+	})
+	return timeline, nil
+}
+
+//* escalation policies
+
+func (s *RedisStorage) CreateEscalationPolicy(ctx context.Context, p *domain.EscalationPolicy) *errs.AppError {
+	//* check if project exists
+	n, err := s.client.Exists(ctx, s.key_ProjectInfo(p.ProjectId)).Result()
+	if err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to get project: project_id=%s, err=%w", p.ProjectId, err))
+	}
+	if n == 0 {
+		return errs.NewNotFound(nil,
+			fmt.Sprintf("project not found: id=%s", p.ProjectId))
+	}
+
+	//* check amount of policies
+	n, err = s.client.ZCard(ctx, s.key_ProjectEscalationPolicies(p.ProjectId)).Result()
+	if err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to get amount of zset members: project_id=%s, err=%w", p.ProjectId, err))
+	}
+	if n >= s.maxPolicies {
+		return errs.NewConflict(nil, fmt.Sprintf("A project cannot have more than %d escalation policies", s.maxPolicies))
+	}
+
+	//* prepare pipeline
+	pipe := s.client.TxPipeline()
+
+	pipe.ZAdd(ctx, s.key_ProjectEscalationPolicies(p.ProjectId), redis.Z{
+		Score:  float64(time.Now().Unix()),
+		Member: p.ID,
+	})
+	pipe.HSet(ctx, s.key_EscalationPolicyInfo(p.ProjectId, p.ID), escalationPolicyToHash(p)...)
+
+	//* execute
+	if _, err := pipe.Exec(ctx); err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to create escalation policy: project_id=%s, err=%w", p.ProjectId, err))
+	}
+	return nil
+}
+
+func (s *RedisStorage) GetEscalationPolicies(ctx context.Context, projectId string) ([]*domain.EscalationPolicy, *errs.AppError) {
+	ids, err := s.client.ZRange(ctx, s.key_ProjectEscalationPolicies(projectId), 0, -1).Result()
+	if err != nil {
+		return nil, errs.NewInternalError(
+			fmt.Errorf("failed to get escalation policies: project_id=%s, err=%w", projectId, err))
+	}
+
+	//* prepare pipeline
+	pipe := s.client.Pipeline()
+
+	infoCmds := make(map[string]*redis.MapStringStringCmd)
+	for _, id := range ids {
+		infoCmds[id] = pipe.HGetAll(ctx, s.key_EscalationPolicyInfo(projectId, id))
+	}
+
+	//* execute
+	if len(ids) > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, errs.NewInternalError(fmt.Errorf("pipe execution failed: %w", err))
+		}
+	}
+
+	//* get result
+	policies := make([]*domain.EscalationPolicy, 0, len(ids))
+	for _, id := range ids {
+		info := infoCmds[id].Val()
+		if len(info) == 0 {
+			log.Printf("WARN: escalation policy info not found, project_id=%s, policy_id=%s\n", projectId, id)
+			continue
+		}
+		policies = append(policies, escalationPolicyFromHash(projectId, id, info))
+	}
+	return policies, nil
+}
+
+func (s *RedisStorage) GetEscalationPolicy(ctx context.Context, projectId, policyId string) (*domain.EscalationPolicy, *errs.AppError) {
+	info, err := s.client.HGetAll(ctx, s.key_EscalationPolicyInfo(projectId, policyId)).Result()
+	if err != nil {
+		return nil, errs.NewInternalError(
+			fmt.Errorf("failed to get escalation policy: id=%s, err=%w", policyId, err))
+	}
+	if len(info) == 0 {
+		return nil, errs.NewNotFound(nil,
+			fmt.Sprintf("escalation policy not found: id=%s", policyId))
+	}
+	return escalationPolicyFromHash(projectId, policyId, info), nil
+}
+
+func (s *RedisStorage) UpdateEscalationPolicy(ctx context.Context, p *domain.EscalationPolicy) *errs.AppError {
+	//* check if policy exists
+	n, err := s.client.Exists(ctx, s.key_EscalationPolicyInfo(p.ProjectId, p.ID)).Result()
+	if err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to get escalation policy: id=%s, err=%w", p.ID, err))
+	}
+	if n == 0 {
+		return errs.NewNotFound(nil,
+			fmt.Sprintf("escalation policy not found: id=%s", p.ID))
+	}
+
+	//* update policy, every field is overwritten
+	if err := s.client.HSet(ctx, s.key_EscalationPolicyInfo(p.ProjectId, p.ID), escalationPolicyToHash(p)...).Err(); err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to update escalation policy: id=%s, err=%w", p.ID, err))
+	}
+	return nil
+}
+
+func (s *RedisStorage) DeleteEscalationPolicy(ctx context.Context, projectId, policyId string) *errs.AppError {
+	//* check if policy exists
+	n, err := s.client.Exists(ctx, s.key_EscalationPolicyInfo(projectId, policyId)).Result()
+	if err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to get escalation policy: id=%s, err=%w", policyId, err))
+	}
+	if n == 0 {
+		return errs.NewNotFound(nil,
+			fmt.Sprintf("escalation policy not found: id=%s", policyId))
+	}
+
+	//* find endpoints that use policy
+	endpointIds, err := s.client.ZRange(ctx, s.key_ProjectEndpoints(projectId), 0, -1).Result()
+	if err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to get endpoints: project_id=%s, err=%w", projectId, err))
+	}
+
+	pipeGet := s.client.Pipeline()
+	policyCmds := make(map[string]*redis.StringCmd)
+	for _, id := range endpointIds {
+		policyCmds[id] = pipeGet.HGet(ctx, s.key_EndpointInfo(projectId, id), EndpointInfo_HSet_EscalationPolicyID)
+	}
+	if len(endpointIds) > 0 {
+		if _, err := pipeGet.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+			return errs.NewInternalError(fmt.Errorf("pipe execution failed: %w", err))
+		}
+	}
+
+	//* prepare pipeline
+	pipe := s.client.TxPipeline()
+
+	pipe.ZRem(ctx, s.key_ProjectEscalationPolicies(projectId), policyId)
+	pipe.Del(ctx, s.key_EscalationPolicyInfo(projectId, policyId))
+	// pending escalations of policy are dropped when they are due
+	for _, id := range endpointIds {
+		if policyCmds[id].Val() == policyId {
+			pipe.HSet(ctx, s.key_EndpointInfo(projectId, id), EndpointInfo_HSet_EscalationPolicyID, "")
+		}
+	}
+
+	//* execute
+	if _, err := pipe.Exec(ctx); err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to delete escalation policy: project_id=%s, policy_id=%s, err=%w", projectId, policyId, err))
+	}
+	return nil
+}
+
+//* escalations
+
+func (s *RedisStorage) ScheduleEscalation(ctx context.Context, e *domain.Escalation) *errs.AppError {
+	//* prepare pipeline
+	pipe := s.client.TxPipeline()
+
+	pipe.HSet(ctx, s.key_IncidentEscalation(e.ProjectId, e.IncidentID), escalationToHash(e)...)
+	pipe.ZAdd(ctx, s.key_Escalations(), redis.Z{
+		Score:  float64(e.DueAt.UnixMilli()),
+		Member: escalationMember(e.ProjectId, e.IncidentID),
+	})
+
+	//* execute
+	if _, err := pipe.Exec(ctx); err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to schedule escalation: project_id=%s, incident_id=%s, err=%w", e.ProjectId, e.IncidentID, err))
+	}
+	return nil
+}
+
+func (s *RedisStorage) GetEscalation(ctx context.Context, projectId, incidentId string) (*domain.Escalation, *errs.AppError) {
+	info, err := s.client.HGetAll(ctx, s.key_IncidentEscalation(projectId, incidentId)).Result()
+	if err != nil {
+		return nil, errs.NewInternalError(
+			fmt.Errorf("failed to get escalation: incident_id=%s, err=%w", incidentId, err))
+	}
+	if len(info) == 0 {
+		return nil, errs.NewNotFound(nil,
+			fmt.Sprintf("escalation not found: incident_id=%s", incidentId))
+	}
+	return escalationFromHash(projectId, incidentId, info), nil
+}
+
+func (s *RedisStorage) GetDueEscalations(ctx context.Context, now time.Time, limit int64) ([]*domain.Escalation, *errs.AppError) {
+	members, err := s.client.ZRangeByScore(ctx, s.key_Escalations(), &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now.UnixMilli(), 10),
+		Count: limit,
+	}).Result()
+	if err != nil {
+		return nil, errs.NewInternalError(
+			fmt.Errorf("failed to get due escalations: err=%w", err))
+	}
+
+	//* prepare pipeline
+	pipe := s.client.Pipeline()
+
+	infoCmds := make(map[string]*redis.MapStringStringCmd)
+	for _, member := range members {
+		if projectId, incidentId, ok := parseEscalationMember(member); ok {
+			infoCmds[member] = pipe.HGetAll(ctx, s.key_IncidentEscalation(projectId, incidentId))
+		}
+	}
+
+	//* execute
+	if len(infoCmds) > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, errs.NewInternalError(fmt.Errorf("pipe execution failed: %w", err))
+		}
+	}
+
+	//* get result
+	escalations := make([]*domain.Escalation, 0, len(members))
+	var stale []any
+	for _, member := range members {
+		projectId, incidentId, ok := parseEscalationMember(member)
+		if !ok || len(infoCmds[member].Val()) == 0 {
+			log.Printf("WARN: escalation info not found, member=%s\n", member)
+			stale = append(stale, member)
+			continue
+		}
+		escalations = append(escalations, escalationFromHash(projectId, incidentId, infoCmds[member].Val()))
+	}
+	// stale members would be returned again and again
+	if len(stale) > 0 {
+		if err := s.client.ZRem(ctx, s.key_Escalations(), stale...).Err(); err != nil {
+			log.Printf("ERR: failed to remove stale escalations, err=%v\n", err)
+		}
+	}
+	return escalations, nil
+}
+
+func (s *RedisStorage) DeleteEscalation(ctx context.Context, projectId, incidentId string) *errs.AppError {
+	//* prepare pipeline
+	pipe := s.client.TxPipeline()
+
+	s.deleteEscalation_AddToPipe(ctx, pipe, projectId, incidentId)
+
+	//* execute
+	if _, err := pipe.Exec(ctx); err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to delete escalation: project_id=%s, incident_id=%s, err=%w", projectId, incidentId, err))
+	}
+	return nil
+}
+
+//* on-call schedules
+
+func (s *RedisStorage) CreateSchedule(ctx context.Context, sc *domain.OnCallSchedule) *errs.AppError {
+	//* check if project exists
+	n, err := s.client.Exists(ctx, s.key_ProjectInfo(sc.ProjectId)).Result()
+	if err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to get project: project_id=%s, err=%w", sc.ProjectId, err))
+	}
+	if n == 0 {
+		return errs.NewNotFound(nil,
+			fmt.Sprintf("project not found: id=%s", sc.ProjectId))
+	}
+
+	//* check amount of schedules
+	n, err = s.client.ZCard(ctx, s.key_ProjectSchedules(sc.ProjectId)).Result()
+	if err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to get amount of zset members: project_id=%s, err=%w", sc.ProjectId, err))
+	}
+	if n >= s.maxSchedules {
+		return errs.NewConflict(nil, fmt.Sprintf("A project cannot have more than %d on-call schedules", s.maxSchedules))
+	}
+
+	//* prepare pipeline
+	pipe := s.client.TxPipeline()
+
+	pipe.ZAdd(ctx, s.key_ProjectSchedules(sc.ProjectId), redis.Z{
+		Score:  float64(time.Now().Unix()),
+		Member: sc.ID,
+	})
+	pipe.HSet(ctx, s.key_ScheduleInfo(sc.ProjectId, sc.ID), scheduleToHash(sc)...)
+
+	//* execute
+	if _, err := pipe.Exec(ctx); err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to create on-call schedule: project_id=%s, err=%w", sc.ProjectId, err))
+	}
+	return nil
+}
+
+func (s *RedisStorage) GetSchedules(ctx context.Context, projectId string) ([]*domain.OnCallSchedule, *errs.AppError) {
+	ids, err := s.client.ZRange(ctx, s.key_ProjectSchedules(projectId), 0, -1).Result()
+	if err != nil {
+		return nil, errs.NewInternalError(
+			fmt.Errorf("failed to get on-call schedules: project_id=%s, err=%w", projectId, err))
+	}
+
+	//* prepare pipeline
+	pipe := s.client.Pipeline()
+
+	infoCmds := make(map[string]*redis.MapStringStringCmd)
+	for _, id := range ids {
+		infoCmds[id] = pipe.HGetAll(ctx, s.key_ScheduleInfo(projectId, id))
+	}
+
+	//* execute
+	if len(ids) > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, errs.NewInternalError(fmt.Errorf("pipe execution failed: %w", err))
+		}
+	}
+
+	//* get result
+	schedules := make([]*domain.OnCallSchedule, 0, len(ids))
+	for _, id := range ids {
+		info := infoCmds[id].Val()
+		if len(info) == 0 {
+			log.Printf("WARN: on-call schedule info not found, project_id=%s, schedule_id=%s\n", projectId, id)
+			continue
+		}
+		schedules = append(schedules, scheduleFromHash(projectId, id, info))
+	}
+	return schedules, nil
+}
+
+func (s *RedisStorage) GetSchedule(ctx context.Context, projectId, scheduleId string) (*domain.OnCallSchedule, *errs.AppError) {
+	info, err := s.client.HGetAll(ctx, s.key_ScheduleInfo(projectId, scheduleId)).Result()
+	if err != nil {
+		return nil, errs.NewInternalError(
+			fmt.Errorf("failed to get on-call schedule: id=%s, err=%w", scheduleId, err))
+	}
+	if len(info) == 0 {
+		return nil, errs.NewNotFound(nil,
+			fmt.Sprintf("on-call schedule not found: id=%s", scheduleId))
+	}
+	return scheduleFromHash(projectId, scheduleId, info), nil
+}
+
+func (s *RedisStorage) UpdateSchedule(ctx context.Context, sc *domain.OnCallSchedule) *errs.AppError {
+	//* check if schedule exists
+	n, err := s.client.Exists(ctx, s.key_ScheduleInfo(sc.ProjectId, sc.ID)).Result()
+	if err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to get on-call schedule: id=%s, err=%w", sc.ID, err))
+	}
+	if n == 0 {
+		return errs.NewNotFound(nil,
+			fmt.Sprintf("on-call schedule not found: id=%s", sc.ID))
+	}
+
+	//* update schedule, every field is overwritten
+	if err := s.client.HSet(ctx, s.key_ScheduleInfo(sc.ProjectId, sc.ID), scheduleToHash(sc)...).Err(); err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to update on-call schedule: id=%s, err=%w", sc.ID, err))
+	}
+	return nil
+}
+
+func (s *RedisStorage) DeleteSchedule(ctx context.Context, projectId, scheduleId string) *errs.AppError {
+	//* check if schedule exists
+	n, err := s.client.Exists(ctx, s.key_ScheduleInfo(projectId, scheduleId)).Result()
+	if err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to get on-call schedule: id=%s, err=%w", scheduleId, err))
+	}
+	if n == 0 {
+		return errs.NewNotFound(nil,
+			fmt.Sprintf("on-call schedule not found: id=%s", scheduleId))
+	}
+
+	//* prepare pipeline
+	pipe := s.client.TxPipeline()
+
+	pipe.ZRem(ctx, s.key_ProjectSchedules(projectId), scheduleId)
+	pipe.Del(ctx,
+		s.key_ScheduleInfo(projectId, scheduleId),
+		s.key_ScheduleOverrides(projectId, scheduleId))
+
+	//* execute
+	if _, err := pipe.Exec(ctx); err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to delete on-call schedule: project_id=%s, schedule_id=%s, err=%w", projectId, scheduleId, err))
+	}
+	return nil
+}
+
+func (s *RedisStorage) AddScheduleOverride(ctx context.Context, projectId, scheduleId string, o *domain.OnCallOverride) *errs.AppError {
+	//* check if schedule exists
+	n, err := s.client.Exists(ctx, s.key_ScheduleInfo(projectId, scheduleId)).Result()
+	if err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to get on-call schedule: id=%s, err=%w", scheduleId, err))
+	}
+	if n == 0 {
+		return errs.NewNotFound(nil,
+			fmt.Sprintf("on-call schedule not found: id=%s", scheduleId))
+	}
+
+	key := s.key_ScheduleOverrides(projectId, scheduleId)
+
+	//* drop ended overrides, then check amount of left ones
+	err = s.client.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(time.Now().UnixMilli(), 10)).Err()
+	if err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to remove ended overrides: schedule_id=%s, err=%w", scheduleId, err))
+	}
+	n, err = s.client.ZCard(ctx, key).Result()
+	if err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to get amount of zset members: schedule_id=%s, err=%w", scheduleId, err))
+	}
+	if n >= domain.MaxScheduleOverrides {
+		return errs.NewConflict(nil, fmt.Sprintf("A schedule cannot have more than %d overrides", domain.MaxScheduleOverrides))
+	}
+
+	member, err := encodeOverrideRecord(o)
+	if err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to encode override: schedule_id=%s, err=%w", scheduleId, err))
+	}
+	err = s.client.ZAdd(ctx, key, redis.Z{
+		Score:  float64(o.End.UnixMilli()),
+		Member: member,
+	}).Err()
+	if err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to add override: project_id=%s, schedule_id=%s, err=%w", projectId, scheduleId, err))
+	}
+	return nil
+}
+
+func (s *RedisStorage) GetScheduleOverrides(ctx context.Context, projectId, scheduleId string, from time.Time) ([]*domain.OnCallOverride, *errs.AppError) {
+	//* check if schedule exists
+	n, err := s.client.Exists(ctx, s.key_ScheduleInfo(projectId, scheduleId)).Result()
+	if err != nil {
+		return nil, errs.NewInternalError(
+			fmt.Errorf("failed to get on-call schedule: id=%s, err=%w", scheduleId, err))
+	}
+	if n == 0 {
+		return nil, errs.NewNotFound(nil,
+			fmt.Sprintf("on-call schedule not found: id=%s", scheduleId))
+	}
+
+	members, err := s.client.ZRangeByScore(ctx, s.key_ScheduleOverrides(projectId, scheduleId), &redis.ZRangeBy{
+		Min: "(" + strconv.FormatInt(from.UnixMilli(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, errs.NewInternalError(
+			fmt.Errorf("failed to get overrides: project_id=%s, schedule_id=%s, err=%w", projectId, scheduleId, err))
+	}
+
+	overrides := make([]*domain.OnCallOverride, 0, len(members))
+	for _, member := range members {
+		o, err := decodeOverrideRecord(member)
+		if err != nil {
+			log.Printf("WARN: failed to decode override record, schedule_id=%s, err=%v\n", scheduleId, err)
+			continue
+		}
+		overrides = append(overrides, o)
+	}
+	return overrides, nil
+}
+
+func (s *RedisStorage) DeleteScheduleOverride(ctx context.Context, projectId, scheduleId, overrideId string) *errs.AppError {
+	key := s.key_ScheduleOverrides(projectId, scheduleId)
+
+	members, err := s.client.ZRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		return errs.NewInternalError(
+			fmt.Errorf("failed to get overrides: project_id=%s, schedule_id=%s, err=%w", projectId, scheduleId, err))
+	}
+
+	for _, member := range members {
+		o, err := decodeOverrideRecord(member)
+		if err != nil || o.ID != overrideId {
+			continue
+		}
+		if err := s.client.ZRem(ctx, key, member).Err(); err != nil {
+			return errs.NewInternalError(
+				fmt.Errorf("failed to delete override: schedule_id=%s, override_id=%s, err=%w", scheduleId, overrideId, err))
+		}
+		return nil
+	}
+	return errs.NewNotFound(nil,
+		fmt.Sprintf("override not found: id=%s", overrideId))
+}
@@ -4,6 +4,11 @@ import "fmt"
 
 //* projects
 
+// ZSet
+func (s RedisStorage) key_Projects() string {
+	return "projects"
+}
+
 // HSet
 func (s RedisStorage) key_ProjectInfo(projectId string) string {
 	return fmt.Sprintf("projects:%s", projectId)
@@ -42,3 +47,94 @@ func (s RedisStorage) key_EndpointInfo(projectId, endpointId string) string {
 func (s RedisStorage) key_EndpointStatus(projectId, endpointId string) string {
 	return fmt.Sprintf("endpoints:%s:%s:status", projectId, endpointId)
 }
+
+// ZSet, score is check time in unix ms
+func (s RedisStorage) key_EndpointHistory(projectId, endpointId string) string {
+	return fmt.Sprintf("endpoints:%s:%s:history", projectId, endpointId)
+}
+
+// HSet
+func (s RedisStorage) key_EndpointHeartbeat(projectId, endpointId string) string {
+	return fmt.Sprintf("endpoints:%s:%s:heartbeat", projectId, endpointId)
+}
+
+//* heartbeat
+
+// HSet
+func (s RedisStorage) key_HeartbeatToken(token string) string {
+	return fmt.Sprintf("heartbeat:%s", token)
+}
+
+//* notification channels
+
+// ZSet
+func (s RedisStorage) key_ProjectChannels(projectId string) string {
+	return fmt.Sprintf("channels:%s", projectId)
+}
+
+// HSet
+func (s RedisStorage) key_ChannelInfo(projectId, channelId string) string {
+	return fmt.Sprintf("channels:%s:%s:info", projectId, channelId)
+}
+
+// ZSet, score is attempt time in unix ms
+func (s RedisStorage) key_ChannelDeliveries(projectId, channelId string) string {
+	return fmt.Sprintf("channels:%s:%s:deliveries", projectId, channelId)
+}
+
+//* incidents
+
+// ZSet, score is opening time in unix ms
+func (s RedisStorage) key_ProjectIncidents(projectId string) string {
+	return fmt.Sprintf("incidents:%s", projectId)
+}
+
+// HSet
+func (s RedisStorage) key_IncidentInfo(projectId, incidentId string) string {
+	return fmt.Sprintf("incidents:%s:%s:info", projectId, incidentId)
+}
+
+// ZSet, score is entry time in unix ms
+func (s RedisStorage) key_IncidentTimeline(projectId, incidentId string) string {
+	return fmt.Sprintf("incidents:%s:%s:timeline", projectId, incidentId)
+}
+
+// HSet, pending escalation of open incident
+func (s RedisStorage) key_IncidentEscalation(projectId, incidentId string) string {
+	return fmt.Sprintf("incidents:%s:%s:escalation", projectId, incidentId)
+}
+
+//* escalation policies
+
+// ZSet
+func (s RedisStorage) key_ProjectEscalationPolicies(projectId string) string {
+	return fmt.Sprintf("escalation_policies:%s", projectId)
+}
+
+// HSet
+func (s RedisStorage) key_EscalationPolicyInfo(projectId, policyId string) string {
+	return fmt.Sprintf("escalation_policies:%s:%s:info", projectId, policyId)
+}
+
+// ZSet of pending escalations of all projects, score is due time in unix ms.
+// Member is `escalationMember()`
+func (s RedisStorage) key_Escalations() string {
+	return "escalations"
+}
+
+//* on-call schedules
+
+// ZSet
+func (s RedisStorage) key_ProjectSchedules(projectId string) string {
+	return fmt.Sprintf("schedules:%s", projectId)
+}
+
+// HSet
+func (s RedisStorage) key_ScheduleInfo(projectId, scheduleId string) string {
+	return fmt.Sprintf("schedules:%s:%s:info", projectId, scheduleId)
+}
+
+// ZSet, score is override end time in unix ms
+func (s RedisStorage) key_ScheduleOverrides(projectId, scheduleId string) string {
+	return fmt.Sprintf("schedules:%s:%s:overrides", projectId, scheduleId)
+}
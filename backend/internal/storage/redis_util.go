@@ -2,6 +2,10 @@ package storage
 
 import (
 	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"strings"
 	"time"
 
 	redis "github.com/redis/go-redis/v9"
@@ -14,6 +18,10 @@ func (s *RedisStorage) setNewProjectInfo_AddToPipe(ctx context.Context, pipe red
 		Project_HSet_Name, project.Name,
 		Project_HSet_AdminKey, project.AdminKey.Key,
 	)
+	pipe.ZAdd(ctx, s.key_Projects(), redis.Z{
+		Score:  float64(time.Now().Unix()),
+		Member: project.ID,
+	})
 }
 
 // * api key
@@ -49,18 +57,693 @@ func (s *RedisStorage) setNewEndpoint_AddToPipe(ctx context.Context, pipe redis.
 		Score:  float64(time.Now().Unix()),
 		Member: ep.ID,
 	})
-	pipe.HSet(ctx, s.key_EndpointInfo(ep.ProjectId, ep.ID),
-		EndpointInfo_HSet_Name, ep.Name,
-		EndpointInfo_HSet_Url, ep.URL,
-		EndpointInfo_HSet_ProjectId, ep.ProjectId)
-	pipe.HSet(ctx, s.key_EndpointStatus(ep.ProjectId, ep.ID),
-		EndpointStatus_HSet_Status, "Unknown",
-		EndpointStatus_HSet_LastChecked, "Never",
-		EndpointStatus_HSet_ResponseTime, "0")
+	pipe.HSet(ctx, s.key_EndpointInfo(ep.ProjectId, ep.ID), endpointInfoToHash(ep)...)
+	pipe.HSet(ctx, s.key_EndpointStatus(ep.ProjectId, ep.ID), endpointStatusToHash(&domain.EndpointStatus{
+		ID:        ep.ID,
+		ProjectId: ep.ProjectId,
+		CheckResult: domain.CheckResult{
+			State: domain.StateUnknown,
+		},
+	})...)
+	s.setHeartbeatToken_AddToPipe(ctx, pipe, ep)
+}
+
+// Register heartbeat token of endpoint and start waiting for first heartbeat.
+// No-op for endpoints without token
+func (s *RedisStorage) setHeartbeatToken_AddToPipe(ctx context.Context, pipe redis.Pipeliner, ep *domain.EndpointInfo) {
+	if ep.HeartbeatToken == "" {
+		return
+	}
+	pipe.HSet(ctx, s.key_HeartbeatToken(ep.HeartbeatToken),
+		HeartbeatToken_HSet_ProjectID, ep.ProjectId,
+		HeartbeatToken_HSet_EndpointID, ep.ID)
+	// keep state of existing heartbeat endpoint
+	pipe.HSetNX(ctx, s.key_EndpointHeartbeat(ep.ProjectId, ep.ID),
+		Heartbeat_HSet_DueAt, ep.HeartbeatDueAt(time.Now()).UnixMilli())
 }
 
 func (s *RedisStorage) deleteEndpoint_AddToPipe(ctx context.Context, pipe redis.Pipeliner, endpointId, projectId string) {
 	pipe.ZRem(ctx, s.key_ProjectEndpoints(projectId), endpointId)
 	pipe.Del(ctx, s.key_EndpointInfo(projectId, endpointId))
 	pipe.Del(ctx, s.key_EndpointStatus(projectId, endpointId))
+	pipe.Del(ctx, s.key_EndpointHistory(projectId, endpointId))
+	pipe.Del(ctx, s.key_EndpointHeartbeat(projectId, endpointId))
+}
+
+// HSet field-value pairs of endpoint info
+func endpointInfoToHash(ep *domain.EndpointInfo) []any {
+	headers, _ := json.Marshal(ep.Headers)
+	assertions, _ := json.Marshal(ep.Assertions)
+	dnsExpected, _ := json.Marshal(ep.DNSExpected)
+	return []any{
+		EndpointInfo_HSet_Name, ep.Name,
+		EndpointInfo_HSet_Type, string(ep.Type),
+		EndpointInfo_HSet_Url, ep.URL,
+		EndpointInfo_HSet_ProjectId, ep.ProjectId,
+		EndpointInfo_HSet_SLOTarget, strconv.FormatFloat(ep.SLOTarget, 'f', -1, 64),
+		EndpointInfo_HSet_EscalationPolicyID, ep.EscalationPolicyID,
+		EndpointInfo_HSet_Interval, int64(ep.Interval.Seconds()),
+		EndpointInfo_HSet_Timeout, ep.Timeout.Milliseconds(),
+		EndpointInfo_HSet_Retries, ep.Retries,
+		EndpointInfo_HSet_FailureThreshold, ep.FailureThreshold,
+		EndpointInfo_HSet_RecoveryThreshold, ep.RecoveryThreshold,
+		EndpointInfo_HSet_RecheckInterval, int64(ep.RecheckInterval.Seconds()),
+		EndpointInfo_HSet_Method, ep.Method,
+		EndpointInfo_HSet_Headers, string(headers),
+		EndpointInfo_HSet_Body, ep.Body,
+		EndpointInfo_HSet_ExpectedStatuses, ep.ExpectedStatuses.String(),
+		EndpointInfo_HSet_RedirectPolicy, ep.RedirectPolicy,
+		EndpointInfo_HSet_Assertions, string(assertions),
+		EndpointInfo_HSet_TCPPayload, ep.TCPPayload,
+		EndpointInfo_HSet_TCPExpectBanner, ep.TCPExpectBanner,
+		EndpointInfo_HSet_DNSRecordType, ep.DNSRecordType,
+		EndpointInfo_HSet_DNSResolver, ep.DNSResolver,
+		EndpointInfo_HSet_DNSExpected, string(dnsExpected),
+		EndpointInfo_HSet_GRPCService, ep.GRPCService,
+		EndpointInfo_HSet_GRPCTLS, formatBool(ep.GRPCTLS),
+		EndpointInfo_HSet_WSMessage, ep.WSMessage,
+		EndpointInfo_HSet_WSExpectPattern, ep.WSExpectPattern,
+		EndpointInfo_HSet_HeartbeatToken, ep.HeartbeatToken,
+		EndpointInfo_HSet_HeartbeatPeriod, int64(ep.HeartbeatPeriod.Seconds()),
+		EndpointInfo_HSet_HeartbeatGrace, int64(ep.HeartbeatGrace.Seconds()),
+		EndpointInfo_HSet_TLSExpiryThresholdDays, ep.TLSExpiryThresholdDays,
+	}
+}
+
+// Build endpoint info from its HSet fields.
+// Invalid optional fields are logged and left with zero values
+func endpointInfoFromHash(id string, info map[string]string) *domain.EndpointInfo {
+	ep := &domain.EndpointInfo{
+		ID:             id,
+		Name:           info[EndpointInfo_HSet_Name],
+		Type:           domain.CheckType(info[EndpointInfo_HSet_Type]),
+		URL:            info[EndpointInfo_HSet_Url],
+		ProjectId:      info[EndpointInfo_HSet_ProjectId],
+		Method:         info[EndpointInfo_HSet_Method],
+		Body:           info[EndpointInfo_HSet_Body],
+		RedirectPolicy: info[EndpointInfo_HSet_RedirectPolicy],
+		// tcp check
+		TCPPayload:      info[EndpointInfo_HSet_TCPPayload],
+		TCPExpectBanner: info[EndpointInfo_HSet_TCPExpectBanner],
+		// dns check
+		DNSRecordType: info[EndpointInfo_HSet_DNSRecordType],
+		DNSResolver:   info[EndpointInfo_HSet_DNSResolver],
+		// grpc check
+		GRPCService: info[EndpointInfo_HSet_GRPCService],
+		GRPCTLS:     info[EndpointInfo_HSet_GRPCTLS] == "1",
+		// websocket check
+		WSMessage:       info[EndpointInfo_HSet_WSMessage],
+		WSExpectPattern: info[EndpointInfo_HSet_WSExpectPattern],
+		// heartbeat check
+		HeartbeatToken:  info[EndpointInfo_HSet_HeartbeatToken],
+		HeartbeatPeriod: parseSeconds(info[EndpointInfo_HSet_HeartbeatPeriod]),
+		HeartbeatGrace:  parseSeconds(info[EndpointInfo_HSet_HeartbeatGrace]),
+	}
+	ep.SLOTarget, _ = strconv.ParseFloat(info[EndpointInfo_HSet_SLOTarget], 64)
+	ep.EscalationPolicyID = info[EndpointInfo_HSet_EscalationPolicyID]
+	ep.TLSExpiryThresholdDays, _ = strconv.Atoi(info[EndpointInfo_HSet_TLSExpiryThresholdDays])
+	ep.Interval = parseSeconds(info[EndpointInfo_HSet_Interval])
+	timeoutMs, _ := strconv.ParseInt(info[EndpointInfo_HSet_Timeout], 10, 64)
+	ep.Timeout = time.Duration(timeoutMs) * time.Millisecond
+	ep.Retries, _ = strconv.Atoi(info[EndpointInfo_HSet_Retries])
+	ep.FailureThreshold, _ = strconv.Atoi(info[EndpointInfo_HSet_FailureThreshold])
+	ep.RecoveryThreshold, _ = strconv.Atoi(info[EndpointInfo_HSet_RecoveryThreshold])
+	ep.RecheckInterval = parseSeconds(info[EndpointInfo_HSet_RecheckInterval])
+
+	var err error
+	if v := info[EndpointInfo_HSet_Headers]; v != "" {
+		if err = json.Unmarshal([]byte(v), &ep.Headers); err != nil {
+			log.Printf("WARN: invalid endpoint headers, id=%s, err=%v\n", id, err)
+		}
+	}
+	if v := info[EndpointInfo_HSet_Assertions]; v != "" {
+		if err = json.Unmarshal([]byte(v), &ep.Assertions); err != nil {
+			log.Printf("WARN: invalid endpoint assertions, id=%s, err=%v\n", id, err)
+		}
+		for i := range ep.Assertions {
+			if err = ep.Assertions[i].Compile(); err != nil {
+				log.Printf("WARN: invalid endpoint assertion regex, id=%s, err=%v\n", id, err)
+			}
+		}
+	}
+	if err = ep.CompileWSPattern(); err != nil {
+		log.Printf("WARN: invalid endpoint websocket expect pattern, id=%s, err=%v\n", id, err)
+	}
+	if v := info[EndpointInfo_HSet_DNSExpected]; v != "" {
+		if err = json.Unmarshal([]byte(v), &ep.DNSExpected); err != nil {
+			log.Printf("WARN: invalid endpoint dns expected values, id=%s, err=%v\n", id, err)
+		}
+	}
+	if ep.ExpectedStatuses, err = domain.ParseStatusRanges(info[EndpointInfo_HSet_ExpectedStatuses]); err != nil {
+		log.Printf("WARN: invalid endpoint expected statuses, id=%s, err=%v\n", id, err)
+	}
+	return ep
+}
+
+// HSet field-value pairs of endpoint status
+func endpointStatusToHash(st *domain.EndpointStatus) []any {
+	lastChecked := ""
+	if !st.LastChecked.IsZero() {
+		lastChecked = st.LastChecked.Format(time.RFC3339)
+	}
+	stateSince := ""
+	if !st.StateSince.IsZero() {
+		stateSince = st.StateSince.Format(time.RFC3339)
+	}
+	stableSince := ""
+	if !st.StableSince.IsZero() {
+		stableSince = st.StableSince.Format(time.RFC3339)
+	}
+	cert := ""
+	if st.Cert != nil {
+		b, _ := json.Marshal(st.Cert)
+		cert = string(b)
+	}
+	return []any{
+		EndpointStatus_HSet_State, string(st.State),
+		EndpointStatus_HSet_StatusCode, st.StatusCode,
+		EndpointStatus_HSet_ErrorClass, string(st.ErrorClass),
+		EndpointStatus_HSet_Error, st.Error,
+		EndpointStatus_HSet_LastChecked, lastChecked,
+		EndpointStatus_HSet_ResponseTime, formatMs(st.Duration),
+		EndpointStatus_HSet_HandshakeTime, formatMs(st.Handshake),
+		EndpointStatus_HSet_RoundTripTime, formatMs(st.RoundTrip),
+		EndpointStatus_HSet_Cert, cert,
+		EndpointStatus_HSet_CheckState, string(st.CheckState),
+		EndpointStatus_HSet_Failures, st.Failures,
+		EndpointStatus_HSet_Successes, st.Successes,
+		EndpointStatus_HSet_StateSince, stateSince,
+		EndpointStatus_HSet_StableState, string(st.StableState),
+		EndpointStatus_HSet_StableSince, stableSince,
+		EndpointStatus_HSet_FlapRecent, st.Recent,
+		EndpointStatus_HSet_FlapRecentCount, st.RecentCount,
+		EndpointStatus_HSet_FlapScore, strconv.FormatFloat(st.FlapScore, 'f', 2, 64),
+		EndpointStatus_HSet_Flapping, formatBool(st.IsFlapping),
+		EndpointStatus_HSet_IncidentID, st.IncidentID,
+	}
+}
+
+// Build check result and last check time from endpoint status HSet fields
+func checkResultFromHash(status map[string]string) (domain.CheckResult, time.Time) {
+	res := domain.CheckResult{
+		State:      domain.State(status[EndpointStatus_HSet_State]),
+		ErrorClass: domain.ErrorClass(status[EndpointStatus_HSet_ErrorClass]),
+		Error:      status[EndpointStatus_HSet_Error],
+		Duration:   parseMs(status[EndpointStatus_HSet_ResponseTime]),
+		Handshake:  parseMs(status[EndpointStatus_HSet_HandshakeTime]),
+		RoundTrip:  parseMs(status[EndpointStatus_HSet_RoundTripTime]),
+	}
+	if res.State == "" {
+		res.State = domain.StateUnknown
+	}
+	res.StatusCode, _ = strconv.Atoi(status[EndpointStatus_HSet_StatusCode])
+	if v := status[EndpointStatus_HSet_Cert]; v != "" {
+		res.Cert = &domain.CertInfo{}
+		if err := json.Unmarshal([]byte(v), res.Cert); err != nil {
+			log.Printf("WARN: invalid endpoint certificate, err=%v\n", err)
+			res.Cert = nil
+		}
+	}
+	lastChecked, _ := time.Parse(time.RFC3339, status[EndpointStatus_HSet_LastChecked])
+	return res, lastChecked
+}
+
+// Parse whole seconds number. Invalid value is parsed as zero
+func parseSeconds(s string) time.Duration {
+	sec, _ := strconv.ParseInt(s, 10, 64)
+	return time.Duration(sec) * time.Second
+}
+
+// Parse unix ms time. Invalid or zero value is parsed as zero time
+func parseUnixMilli(s string) time.Time {
+	ms, _ := strconv.ParseInt(s, 10, 64)
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
+
+// Format time as unix ms, zero time is formatted as 0
+func formatUnixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+// Format bool as "1" or "0"
+func formatBool(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// Build state confirmation from endpoint status HSet fields
+func confirmationFromHash(status map[string]string) domain.Confirmation {
+	c := domain.Confirmation{
+		CheckState: domain.State(status[EndpointStatus_HSet_CheckState]),
+	}
+	c.Failures, _ = strconv.Atoi(status[EndpointStatus_HSet_Failures])
+	c.Successes, _ = strconv.Atoi(status[EndpointStatus_HSet_Successes])
+	c.StateSince, _ = time.Parse(time.RFC3339, status[EndpointStatus_HSet_StateSince])
+	c.StableState = domain.State(status[EndpointStatus_HSet_StableState])
+	c.StableSince, _ = time.Parse(time.RFC3339, status[EndpointStatus_HSet_StableSince])
+
+	// status saved before stable state was tracked
+	if state := domain.State(status[EndpointStatus_HSet_State]); c.StableState == "" && state != domain.StateFlapping {
+		c.StableState = state
+		c.StableSince = c.StateSince
+	}
+	return c
+}
+
+// Build flap detection from endpoint status HSet fields
+func flapDetectionFromHash(status map[string]string) domain.FlapDetection {
+	f := domain.FlapDetection{
+		IsFlapping: status[EndpointStatus_HSet_Flapping] == "1",
+	}
+	recent, _ := strconv.ParseUint(status[EndpointStatus_HSet_FlapRecent], 10, 32)
+	f.Recent = uint32(recent)
+	f.RecentCount, _ = strconv.Atoi(status[EndpointStatus_HSet_FlapRecentCount])
+	f.FlapScore, _ = strconv.ParseFloat(status[EndpointStatus_HSet_FlapScore], 64)
+	return f
+}
+
+// Format duration as milliseconds number
+func formatMs(d time.Duration) string {
+	return strconv.FormatFloat(domain.Milliseconds(d), 'f', 3, 64)
+}
+
+// Parse milliseconds number formatted by `formatMs`. Invalid value is parsed as zero
+func parseMs(s string) time.Duration {
+	ms, _ := strconv.ParseFloat(s, 64)
+	return time.Duration(ms * float64(time.Millisecond))
+}
+
+// * history
+
+// Endpoint history ZSet member
+type historyRecord struct {
+	CheckedAt  int64   `json:"t"`
+	State      string  `json:"st"`
+	StatusCode int     `json:"c,omitempty"`
+	ErrorClass string  `json:"ec,omitempty"`
+	Error      string  `json:"e,omitempty"`
+	DurationMs float64 `json:"ms"`
+	// websocket check
+	HandshakeMs float64 `json:"hs,omitempty"`
+	RoundTripMs float64 `json:"rt,omitempty"`
+}
+
+func encodeHistoryRecord(check *domain.EndpointCheck) (string, error) {
+	b, err := json.Marshal(&historyRecord{
+		CheckedAt:  check.CheckedAt.UnixNano(),
+		State:      string(check.State),
+		StatusCode: check.StatusCode,
+		ErrorClass: string(check.ErrorClass),
+		Error:      check.Error,
+		DurationMs: domain.Milliseconds(check.Duration),
+
+		HandshakeMs: domain.Milliseconds(check.Handshake),
+		RoundTripMs: domain.Milliseconds(check.RoundTrip),
+	})
+	return string(b), err
+}
+
+func decodeHistoryRecord(endpointId, member string) (*domain.EndpointCheck, error) {
+	var rec historyRecord
+	if err := json.Unmarshal([]byte(member), &rec); err != nil {
+		return nil, err
+	}
+	return &domain.EndpointCheck{
+		EndpointID: endpointId,
+		CheckedAt:  time.Unix(0, rec.CheckedAt),
+		CheckResult: domain.CheckResult{
+			State:      domain.State(rec.State),
+			StatusCode: rec.StatusCode,
+			ErrorClass: domain.ErrorClass(rec.ErrorClass),
+			Error:      rec.Error,
+			Duration:   time.Duration(rec.DurationMs * float64(time.Millisecond)),
+			Handshake:  time.Duration(rec.HandshakeMs * float64(time.Millisecond)),
+			RoundTrip:  time.Duration(rec.RoundTripMs * float64(time.Millisecond)),
+		},
+	}, nil
+}
+
+// * notification channels
+
+func (s *RedisStorage) deleteChannel_AddToPipe(ctx context.Context, pipe redis.Pipeliner, projectId, channelId string) {
+	pipe.ZRem(ctx, s.key_ProjectChannels(projectId), channelId)
+	pipe.Del(ctx, s.key_ChannelInfo(projectId, channelId))
+	pipe.Del(ctx, s.key_ChannelDeliveries(projectId, channelId))
+}
+
+// HSet field-value pairs of notification channel
+func channelToHash(ch *domain.NotificationChannel) []any {
+	recipients, _ := json.Marshal(ch.SMTPRecipients)
+	return []any{
+		Channel_HSet_Name, ch.Name,
+		Channel_HSet_Type, string(ch.Type),
+		Channel_HSet_Enabled, formatBool(ch.Enabled),
+		Channel_HSet_WebhookURL, ch.WebhookURL,
+		Channel_HSet_WebhookSecret, ch.WebhookSecret,
+		Channel_HSet_SMTPHost, ch.SMTPHost,
+		Channel_HSet_SMTPPort, ch.SMTPPort,
+		Channel_HSet_SMTPStartTLS, formatBool(ch.SMTPStartTLS),
+		Channel_HSet_SMTPUsername, ch.SMTPUsername,
+		Channel_HSet_SMTPPassword, ch.SMTPPassword,
+		Channel_HSet_SMTPFrom, ch.SMTPFrom,
+		Channel_HSet_SMTPRecipients, string(recipients),
+		Channel_HSet_TelegramAPIURL, ch.TelegramAPIURL,
+		Channel_HSet_TelegramBotToken, ch.TelegramBotToken,
+		Channel_HSet_TelegramChatID, ch.TelegramChatID,
+	}
+}
+
+// Build notification channel from its HSet fields.
+// Invalid optional fields are logged and left with zero values
+func channelFromHash(projectId, id string, info map[string]string) *domain.NotificationChannel {
+	ch := &domain.NotificationChannel{
+		ID:            id,
+		ProjectId:     projectId,
+		Name:          info[Channel_HSet_Name],
+		Type:          domain.ChannelType(info[Channel_HSet_Type]),
+		Enabled:       info[Channel_HSet_Enabled] == "1",
+		WebhookURL:    info[Channel_HSet_WebhookURL],
+		WebhookSecret: info[Channel_HSet_WebhookSecret],
+		SMTPHost:      info[Channel_HSet_SMTPHost],
+		SMTPStartTLS:  info[Channel_HSet_SMTPStartTLS] == "1",
+		SMTPUsername:  info[Channel_HSet_SMTPUsername],
+		SMTPPassword:  info[Channel_HSet_SMTPPassword],
+		SMTPFrom:      info[Channel_HSet_SMTPFrom],
+		// telegram
+		TelegramAPIURL:   info[Channel_HSet_TelegramAPIURL],
+		TelegramBotToken: info[Channel_HSet_TelegramBotToken],
+		TelegramChatID:   info[Channel_HSet_TelegramChatID],
+	}
+	ch.SMTPPort, _ = strconv.Atoi(info[Channel_HSet_SMTPPort])
+	if v := info[Channel_HSet_SMTPRecipients]; v != "" {
+		if err := json.Unmarshal([]byte(v), &ch.SMTPRecipients); err != nil {
+			log.Printf("WARN: invalid smtp recipients of channel, id=%s, err=%v\n", id, err)
+		}
+	}
+	return ch
+}
+
+// Channel deliveries ZSet member
+type deliveryRecord struct {
+	ID         string  `json:"id"`
+	Event      string  `json:"ev"`
+	EndpointID string  `json:"ep"`
+	Attempt    int     `json:"n"`
+	At         int64   `json:"t"`
+	DurationMs float64 `json:"ms"`
+	StatusCode int     `json:"c,omitempty"`
+	Error      string  `json:"e,omitempty"`
+	Success    bool    `json:"ok"`
+}
+
+func encodeDeliveryRecord(d *domain.Delivery) (string, error) {
+	b, err := json.Marshal(&deliveryRecord{
+		ID:         d.ID,
+		Event:      string(d.Event),
+		EndpointID: d.EndpointID,
+		Attempt:    d.Attempt,
+		At:         d.At.UnixNano(),
+		DurationMs: domain.Milliseconds(d.Duration),
+		StatusCode: d.StatusCode,
+		Error:      d.Error,
+		Success:    d.Success,
+	})
+	return string(b), err
+}
+
+func decodeDeliveryRecord(channelId, member string) (*domain.Delivery, error) {
+	var rec deliveryRecord
+	if err := json.Unmarshal([]byte(member), &rec); err != nil {
+		return nil, err
+	}
+	return &domain.Delivery{
+		ID:         rec.ID,
+		ChannelID:  channelId,
+		Event:      domain.Event(rec.Event),
+		EndpointID: rec.EndpointID,
+		Attempt:    rec.Attempt,
+		At:         time.Unix(0, rec.At),
+		Duration:   time.Duration(rec.DurationMs * float64(time.Millisecond)),
+		StatusCode: rec.StatusCode,
+		Error:      rec.Error,
+		Success:    rec.Success,
+	}, nil
+}
+
+// * incidents
+
+// HSet field-value pairs of incident
+func incidentToHash(inc *domain.Incident) []any {
+	return []any{
+		Incident_HSet_EndpointID, inc.EndpointID,
+		Incident_HSet_EndpointName, inc.EndpointName,
+		Incident_HSet_State, string(inc.State),
+		Incident_HSet_Cause, inc.Cause,
+		Incident_HSet_OpenedAt, formatUnixMilli(inc.OpenedAt),
+		Incident_HSet_AcknowledgedAt, formatUnixMilli(inc.AcknowledgedAt),
+		Incident_HSet_AcknowledgedBy, inc.AcknowledgedBy,
+		Incident_HSet_ResolvedAt, formatUnixMilli(inc.ResolvedAt),
+		Incident_HSet_ResolvedBy, inc.ResolvedBy,
+	}
+}
+
+// Build incident from its HSet fields
+func incidentFromHash(projectId, id string, info map[string]string) *domain.Incident {
+	return &domain.Incident{
+		ID:             id,
+		ProjectId:      projectId,
+		EndpointID:     info[Incident_HSet_EndpointID],
+		EndpointName:   info[Incident_HSet_EndpointName],
+		State:          domain.IncidentState(info[Incident_HSet_State]),
+		Cause:          info[Incident_HSet_Cause],
+		OpenedAt:       parseUnixMilli(info[Incident_HSet_OpenedAt]),
+		AcknowledgedAt: parseUnixMilli(info[Incident_HSet_AcknowledgedAt]),
+		AcknowledgedBy: info[Incident_HSet_AcknowledgedBy],
+		ResolvedAt:     parseUnixMilli(info[Incident_HSet_ResolvedAt]),
+		ResolvedBy:     info[Incident_HSet_ResolvedBy],
+	}
+}
+
+// Incident timeline ZSet member
+type timelineRecord struct {
+	At      int64  `json:"t"`
+	Type    string `json:"ty"`
+	State   string `json:"st,omitempty"`
+	Message string `json:"m,omitempty"`
+	Author  string `json:"a,omitempty"`
+}
+
+func encodeTimelineRecord(e *domain.TimelineEntry) (string, error) {
+	b, err := json.Marshal(&timelineRecord{
+		At:      e.At.UnixNano(),
+		Type:    string(e.Type),
+		State:   e.State,
+		Message: e.Message,
+		Author:  e.Author,
+	})
+	return string(b), err
+}
+
+func decodeTimelineRecord(member string) (*domain.TimelineEntry, error) {
+	var rec timelineRecord
+	if err := json.Unmarshal([]byte(member), &rec); err != nil {
+		return nil, err
+	}
+	return &domain.TimelineEntry{
+		At:      time.Unix(0, rec.At),
+		Type:    domain.TimelineEntryType(rec.Type),
+		State:   rec.State,
+		Message: rec.Message,
+		Author:  rec.Author,
+	}, nil
+}
+
+// * escalation
+
+// Escalation policy step stored in steps JSON array
+type escalationStepRecord struct {
+	DelaySec    int64    `json:"delay_sec"`
+	ChannelIDs  []string `json:"channel_ids"`
+	ScheduleIDs []string `json:"schedule_ids,omitempty"`
+}
+
+// HSet field-value pairs of escalation policy
+func escalationPolicyToHash(p *domain.EscalationPolicy) []any {
+	steps := make([]escalationStepRecord, len(p.Steps))
+	for i, step := range p.Steps {
+		steps[i] = escalationStepRecord{
+			DelaySec:    int64(step.Delay.Seconds()),
+			ChannelIDs:  step.ChannelIDs,
+			ScheduleIDs: step.ScheduleIDs,
+		}
+	}
+	stepsJSON, _ := json.Marshal(steps)
+	return []any{
+		EscalationPolicy_HSet_Name, p.Name,
+		EscalationPolicy_HSet_Steps, string(stepsJSON),
+	}
+}
+
+// Build escalation policy from its HSet fields. Invalid steps are logged and left empty
+func escalationPolicyFromHash(projectId, id string, info map[string]string) *domain.EscalationPolicy {
+	p := &domain.EscalationPolicy{
+		ID:        id,
+		ProjectId: projectId,
+		Name:      info[EscalationPolicy_HSet_Name],
+	}
+	var steps []escalationStepRecord
+	if err := json.Unmarshal([]byte(info[EscalationPolicy_HSet_Steps]), &steps); err != nil {
+		log.Printf("WARN: invalid escalation policy steps, policy_id=%s, err=%v\n", id, err)
+		return p
+	}
+	p.Steps = make([]domain.EscalationStep, len(steps))
+	for i, step := range steps {
+		p.Steps[i] = domain.EscalationStep{
+			Delay:       time.Duration(step.DelaySec) * time.Second,
+			ChannelIDs:  step.ChannelIDs,
+			ScheduleIDs: step.ScheduleIDs,
+		}
+	}
+	return p
+}
+
+// HSet field-value pairs of pending escalation
+func escalationToHash(e *domain.Escalation) []any {
+	return []any{
+		Escalation_HSet_PolicyID, e.PolicyID,
+		Escalation_HSet_Step, e.Step,
+		Escalation_HSet_Round, e.Round,
+		Escalation_HSet_DueAt, formatUnixMilli(e.DueAt),
+	}
+}
+
+// Build pending escalation from its HSet fields
+func escalationFromHash(projectId, incidentId string, info map[string]string) *domain.Escalation {
+	e := &domain.Escalation{
+		ProjectId:  projectId,
+		IncidentID: incidentId,
+		PolicyID:   info[Escalation_HSet_PolicyID],
+		DueAt:      parseUnixMilli(info[Escalation_HSet_DueAt]),
+	}
+	e.Step, _ = strconv.Atoi(info[Escalation_HSet_Step])
+	e.Round, _ = strconv.Atoi(info[Escalation_HSet_Round])
+	return e
+}
+
+// Member of pending escalations ZSet
+func escalationMember(projectId, incidentId string) string {
+	return projectId + ":" + incidentId
+}
+
+// Project and incident ids of pending escalations ZSet member. Incident id never holds colon
+func parseEscalationMember(member string) (projectId, incidentId string, ok bool) {
+	i := strings.LastIndex(member, ":")
+	if i < 0 {
+		return "", "", false
+	}
+	return member[:i], member[i+1:], true
+}
+
+// Delete pending escalation of incident
+func (s *RedisStorage) deleteEscalation_AddToPipe(ctx context.Context, pipe redis.Pipeliner, projectId, incidentId string) {
+	pipe.Del(ctx, s.key_IncidentEscalation(projectId, incidentId))
+	pipe.ZRem(ctx, s.key_Escalations(), escalationMember(projectId, incidentId))
+}
+
+// * on-call schedules
+
+// Participant stored in schedule participants JSON array and in override record
+type participantRecord struct {
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+}
+
+// HSet field-value pairs of on-call schedule
+func scheduleToHash(sc *domain.OnCallSchedule) []any {
+	participants := make([]participantRecord, len(sc.Participants))
+	for i, p := range sc.Participants {
+		participants[i] = participantRecord{Name: p.Name, Email: p.Email}
+	}
+	participantsJSON, _ := json.Marshal(participants)
+	return []any{
+		Schedule_HSet_Name, sc.Name,
+		Schedule_HSet_Timezone, sc.Timezone,
+		Schedule_HSet_Rotation, string(sc.Rotation),
+		Schedule_HSet_HandoffTime, int64(sc.HandoffTime / time.Minute),
+		Schedule_HSet_HandoffWeekday, int(sc.HandoffWeekday),
+		Schedule_HSet_Start, formatUnixMilli(sc.Start),
+		Schedule_HSet_Participants, string(participantsJSON),
+	}
+}
+
+// Build on-call schedule from its HSet fields. Invalid participants are logged and left empty
+func scheduleFromHash(projectId, id string, info map[string]string) *domain.OnCallSchedule {
+	sc := &domain.OnCallSchedule{
+		ID:        id,
+		ProjectId: projectId,
+		Name:      info[Schedule_HSet_Name],
+		Timezone:  info[Schedule_HSet_Timezone],
+		Rotation:  domain.RotationType(info[Schedule_HSet_Rotation]),
+		Start:     parseUnixMilli(info[Schedule_HSet_Start]),
+	}
+	handoffMinutes, _ := strconv.ParseInt(info[Schedule_HSet_HandoffTime], 10, 64)
+	sc.HandoffTime = time.Duration(handoffMinutes) * time.Minute
+	weekday, _ := strconv.Atoi(info[Schedule_HSet_HandoffWeekday])
+	sc.HandoffWeekday = time.Weekday(weekday)
+
+	var participants []participantRecord
+	if err := json.Unmarshal([]byte(info[Schedule_HSet_Participants]), &participants); err != nil {
+		log.Printf("WARN: invalid schedule participants, schedule_id=%s, err=%v\n", id, err)
+		return sc
+	}
+	sc.Participants = make([]domain.OnCallParticipant, len(participants))
+	for i, p := range participants {
+		sc.Participants[i] = domain.OnCallParticipant{Name: p.Name, Email: p.Email}
+	}
+	return sc
+}
+
+// Schedule overrides ZSet member
+type overrideRecord struct {
+	ID    string `json:"id"`
+	Start int64  `json:"s"`
+	End   int64  `json:"e"`
+	participantRecord
+}
+
+func encodeOverrideRecord(o *domain.OnCallOverride) (string, error) {
+	b, err := json.Marshal(&overrideRecord{
+		ID:    o.ID,
+		Start: o.Start.UnixMilli(),
+		End:   o.End.UnixMilli(),
+		participantRecord: participantRecord{
+			Name:  o.Participant.Name,
+			Email: o.Participant.Email,
+		},
+	})
+	return string(b), err
+}
+
+func decodeOverrideRecord(member string) (*domain.OnCallOverride, error) {
+	var rec overrideRecord
+	if err := json.Unmarshal([]byte(member), &rec); err != nil {
+		return nil, err
+	}
+	return &domain.OnCallOverride{
+		ID:    rec.ID,
+		Start: time.UnixMilli(rec.Start),
+		End:   time.UnixMilli(rec.End),
+		Participant: domain.OnCallParticipant{
+			Name:  rec.Name,
+			Email: rec.Email,
+		},
+	}, nil
 }
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"time"
 
 	"github.com/wrtgvr/websites-monitor/internal/domain"
 	errs "github.com/wrtgvr/websites-monitor/internal/errors"
@@ -12,6 +13,9 @@ type Storage interface {
 	Close() error
 	//* Projects
 	CreateProject(ctx context.Context, projectInfo *domain.Project) *errs.AppError
+	GetProjectIDs(ctx context.Context) (projectIds []string, appErr *errs.AppError)
+	// Add projects created before project list was kept to it, so they are monitored
+	BackfillProjectIDs(ctx context.Context) *errs.AppError
 	GetProjectIDByAPIKey(ctx context.Context, apiKey string) (projectId string, appErr *errs.AppError)
 	GetProjectInfo(ctx context.Context, projectId string) (project *domain.Project, appErr *errs.AppError)
 	ChangeProjectName(ctx context.Context, projectId, newName string) *errs.AppError
@@ -25,8 +29,68 @@ type Storage interface {
 	//* Endpoints
 	CreateEndpoint(ctx context.Context, endpointInfo *domain.EndpointInfo) *errs.AppError
 	GetEndpoints(ctx context.Context, projectId, endpointId string) (endpoints []*domain.Endpoint, appErr *errs.AppError)
+	// Endpoints in creation order, `limit` endpoints after first `offset` ones
+	GetEndpointsPage(ctx context.Context, projectId string, limit, offset int64) (endpoints []*domain.Endpoint, appErr *errs.AppError)
 	GetEndpointsForMonitoring(ctx context.Context, projectId string) (endpointsInfo []*domain.EndpointInfo, appErr *errs.AppError)
 	UpdateEndpointInfo(ctx context.Context, endpointInfo *domain.EndpointInfo) *errs.AppError
+	GetEndpointStatus(ctx context.Context, projectId, endpointId string) (endpointStatus *domain.EndpointStatus, appErr *errs.AppError)
 	UpdateEndpointStatus(ctx context.Context, projectId string, endpointStatus *domain.EndpointStatus) *errs.AppError
+	// Active incident of endpoint is resolved and its escalation is deleted
 	DeleteEndpoint(ctx context.Context, projectId string, endpointId string) *errs.AppError
+	//* Heartbeat
+	GetEndpointByHeartbeatToken(ctx context.Context, token string) (endpointInfo *domain.EndpointInfo, appErr *errs.AppError)
+	GetHeartbeatState(ctx context.Context, projectId, endpointId string) (state *domain.HeartbeatState, appErr *errs.AppError)
+	UpdateHeartbeatState(ctx context.Context, projectId, endpointId string, state *domain.HeartbeatState) *errs.AppError
+	//* History
+	AddEndpointCheck(ctx context.Context, projectId string, check *domain.EndpointCheck) *errs.AppError
+	// limit 0 means no limit
+	GetEndpointHistory(ctx context.Context, projectId, endpointId string, from, to time.Time, limit, offset int64) (checks []*domain.EndpointCheck, total int64, appErr *errs.AppError)
+	// Pass checks within window to `fn` in time order, reading history in batches
+	ScanEndpointHistory(ctx context.Context, projectId, endpointId string, from, to time.Time, fn func(check *domain.EndpointCheck)) *errs.AppError
+	//* Notification channels
+	CreateNotificationChannel(ctx context.Context, channel *domain.NotificationChannel) *errs.AppError
+	GetNotificationChannels(ctx context.Context, projectId string) (channels []*domain.NotificationChannel, appErr *errs.AppError)
+	GetNotificationChannel(ctx context.Context, projectId, channelId string) (channel *domain.NotificationChannel, appErr *errs.AppError)
+	UpdateNotificationChannel(ctx context.Context, channel *domain.NotificationChannel) *errs.AppError
+	DeleteNotificationChannel(ctx context.Context, projectId, channelId string) *errs.AppError
+	AddNotificationDelivery(ctx context.Context, projectId string, delivery *domain.Delivery) *errs.AppError
+	// Newest deliveries first
+	GetNotificationDeliveries(ctx context.Context, projectId, channelId string, limit, offset int64) (deliveries []*domain.Delivery, total int64, appErr *errs.AppError)
+	//* Incidents
+	CreateIncident(ctx context.Context, incident *domain.Incident, timeline []*domain.TimelineEntry) *errs.AppError
+	GetIncident(ctx context.Context, projectId, incidentId string) (incident *domain.Incident, appErr *errs.AppError)
+	// Newest incidents first, time range applies to opening time
+	GetIncidents(ctx context.Context, projectId string, filter *domain.IncidentFilter, from, to time.Time, limit, offset int64) (incidents []*domain.Incident, total int64, appErr *errs.AppError)
+	// Apply `update` to current incident and save it. Error of `update` is returned as conflict.
+	// Pending escalation is deleted once incident is not open
+	UpdateIncident(ctx context.Context, projectId, incidentId string, update func(incident *domain.Incident) error) (incident *domain.Incident, appErr *errs.AppError)
+	AddIncidentTimelineEntry(ctx context.Context, projectId, incidentId string, entry *domain.TimelineEntry) *errs.AppError
+	// Oldest entries first
+	GetIncidentTimeline(ctx context.Context, projectId, incidentId string) (timeline []*domain.TimelineEntry, appErr *errs.AppError)
+	//* Escalation policies
+	CreateEscalationPolicy(ctx context.Context, policy *domain.EscalationPolicy) *errs.AppError
+	GetEscalationPolicies(ctx context.Context, projectId string) (policies []*domain.EscalationPolicy, appErr *errs.AppError)
+	GetEscalationPolicy(ctx context.Context, projectId, policyId string) (policy *domain.EscalationPolicy, appErr *errs.AppError)
+	UpdateEscalationPolicy(ctx context.Context, policy *domain.EscalationPolicy) *errs.AppError
+	// Endpoints that use policy are left without escalation policy
+	DeleteEscalationPolicy(ctx context.Context, projectId, policyId string) *errs.AppError
+	//* Escalations
+	// Create or replace pending escalation of incident
+	ScheduleEscalation(ctx context.Context, escalation *domain.Escalation) *errs.AppError
+	GetEscalation(ctx context.Context, projectId, incidentId string) (escalation *domain.Escalation, appErr *errs.AppError)
+	// Escalations of all projects due at `now`, earliest first
+	GetDueEscalations(ctx context.Context, now time.Time, limit int64) (escalations []*domain.Escalation, appErr *errs.AppError)
+	DeleteEscalation(ctx context.Context, projectId, incidentId string) *errs.AppError
+	//* On-call schedules
+	CreateSchedule(ctx context.Context, schedule *domain.OnCallSchedule) *errs.AppError
+	GetSchedules(ctx context.Context, projectId string) (schedules []*domain.OnCallSchedule, appErr *errs.AppError)
+	GetSchedule(ctx context.Context, projectId, scheduleId string) (schedule *domain.OnCallSchedule, appErr *errs.AppError)
+	UpdateSchedule(ctx context.Context, schedule *domain.OnCallSchedule) *errs.AppError
+	// Escalation steps that use schedule skip it
+	DeleteSchedule(ctx context.Context, projectId, scheduleId string) *errs.AppError
+	// Ended overrides are dropped
+	AddScheduleOverride(ctx context.Context, projectId, scheduleId string, override *domain.OnCallOverride) *errs.AppError
+	// Overrides that end after `from`, earliest end first
+	GetScheduleOverrides(ctx context.Context, projectId, scheduleId string, from time.Time) (overrides []*domain.OnCallOverride, appErr *errs.AppError)
+	DeleteScheduleOverride(ctx context.Context, projectId, scheduleId, overrideId string) *errs.AppError
 }